@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Option is a function type that modifies App configuration.
+type Option func(*App)
+
+// App runs a set of components with a single signal handler. Components are
+// started in dependency order and stopped in reverse order.
+//
+// Fields:
+//   - Name: Application name used in logs
+//   - Components: Registered components in registration order
+//   - ShutdownTimeout: Maximum time to stop every component (0 uses the largest component StopTimeout)
+//   - Signals: Signals that trigger a graceful shutdown
+type App struct {
+	Name            string
+	Components      []Component
+	ShutdownTimeout time.Duration
+	Signals         []os.Signal
+}
+
+// New creates a new App with the provided options. By default it shuts down on
+// SIGINT and SIGTERM.
+//
+// Parameters:
+//   - options: Variable number of Option functions to configure the app
+//
+// Returns:
+//   - *App: A new configured App instance
+//
+// Example:
+//
+//	a := app.New(
+//	    app.AddName("order-service"),
+//	    app.AddComponents(
+//	        app.LoggerComponent(cfg.Logger.ToConfig()),
+//	        app.DatabaseComponent("postgres", cfg.Postgres.NewDatabase, repo.SetDatabase),
+//	        app.HttpServerComponent(srv, "postgres"),
+//	    ),
+//	)
+//	if err := a.Run(context.Background()); err != nil {
+//	    log.Fatal(err)
+//	}
+func New(options ...Option) *App {
+	a := &App{
+		Signals: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// AddName returns an Option to set the application name.
+//
+// Parameters:
+//   - name: Application name used in logs
+//
+// Returns:
+//   - Option: Function that sets the name
+func AddName(name string) Option {
+	return func(a *App) {
+		a.Name = name
+	}
+}
+
+// AddComponents returns an Option to register components.
+//
+// Parameters:
+//   - components: Components to register
+//
+// Returns:
+//   - Option: Function that appends the components
+func AddComponents(components ...Component) Option {
+	return func(a *App) {
+		a.Components = append(a.Components, components...)
+	}
+}
+
+// SetShutdownTimeout returns an Option to set the maximum time allowed for stopping every component.
+//
+// Parameters:
+//   - t: Shutdown timeout
+//
+// Returns:
+//   - Option: Function that sets the timeout
+func SetShutdownTimeout(t time.Duration) Option {
+	return func(a *App) {
+		a.ShutdownTimeout = t
+	}
+}
+
+// SetSignals returns an Option to set the signals that trigger a graceful shutdown.
+// Passing no signals disables signal handling; the app then stops when its context is cancelled.
+//
+// Parameters:
+//   - signals: Signals to listen for
+//
+// Returns:
+//   - Option: Function that sets the signals
+func SetSignals(signals ...os.Signal) Option {
+	return func(a *App) {
+		a.Signals = signals
+	}
+}
+
+// Register appends components to the app.
+//
+// Parameters:
+//   - components: Components to register
+func (a *App) Register(components ...Component) {
+	a.Components = append(a.Components, components...)
+}
+
+// Run starts every component in dependency order and blocks until ctx is
+// cancelled, a shutdown signal is received or a component's Run hook fails.
+// Components are then stopped in reverse order within the shutdown timeout.
+//
+// Parameters:
+//   - ctx: Parent context; cancelling it stops the app
+//
+// Returns:
+//   - error: Errors from starting, running or stopping components, joined together
+func (a *App) Run(ctx context.Context) error {
+	log := zap.L().With(zap.String("prefix", "Run")).Sugar()
+
+	ordered, err := sortComponents(a.Components)
+	if err != nil {
+		return err
+	}
+
+	if len(a.Signals) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, a.Signals...)
+		defer stop()
+	}
+
+	runErrs := make(chan error, len(ordered))
+	started := make([]*runningComponent, 0, len(ordered))
+
+	for _, component := range ordered {
+		running, err := startComponent(ctx, component, runErrs)
+		if err != nil {
+			err = fmt.Errorf("failed to start %s: %w", component.Name, err)
+			return errors.Join(err, a.stop(started))
+		}
+		started = append(started, running)
+	}
+
+	log.Infof("%s started", a.displayName())
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Infof("shutting down %s...", a.displayName())
+	case runErr = <-runErrs:
+		log.Errorf("shutting down %s: %v", a.displayName(), runErr)
+	}
+
+	if err := errors.Join(runErr, a.stop(started)); err != nil {
+		return err
+	}
+
+	log.Infof("%s exited gracefully", a.displayName())
+	return nil
+}
+
+// stop stops the started components in reverse order within the shutdown timeout.
+func (a *App) stop(started []*runningComponent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
+	defer cancel()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := started[i].stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", started[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// shutdownTimeout returns the configured timeout, or the largest component StopTimeout.
+func (a *App) shutdownTimeout() time.Duration {
+	if a.ShutdownTimeout > 0 {
+		return a.ShutdownTimeout
+	}
+
+	timeout := time.Duration(0)
+	for _, component := range a.Components {
+		timeout = max(timeout, component.StopTimeout)
+	}
+	if timeout == 0 {
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
+
+func (a *App) displayName() string {
+	if a.Name == "" {
+		return "app"
+	}
+	return a.Name
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func recorded(r *recorder, name string, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(context.Context) error {
+			r.add("start " + name)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.add("stop " + name)
+			return nil
+		},
+	}
+}
+
+func TestRunOrdersComponents(t *testing.T) {
+	r := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ready := Component{
+		Name:      "ready",
+		DependsOn: []string{"http"},
+		Start: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	}
+
+	a := New(
+		SetSignals(),
+		AddComponents(recorded(r, "http", "db"), recorded(r, "db", "logger")),
+		AddComponents(recorded(r, "logger"), ready),
+	)
+	require.NoError(t, a.Run(ctx))
+	assert.Equal(t, []string{
+		"start logger", "start db", "start http",
+		"stop http", "stop db", "stop logger",
+	}, r.get())
+}
+
+func TestRunSortErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		wantErr    string
+	}{
+		{
+			name:       "unknown dependency",
+			components: []Component{{Name: "http", DependsOn: []string{"db"}}},
+			wantErr:    "component http depends on unknown component db",
+		},
+		{
+			name:       "cycle",
+			components: []Component{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}},
+			wantErr:    "dependency cycle: a -> b -> a",
+		},
+		{
+			name:       "duplicate",
+			components: []Component{{Name: "a"}, {Name: "a"}},
+			wantErr:    "duplicate component: a",
+		},
+		{
+			name:       "missing name",
+			components: []Component{{}},
+			wantErr:    "component 0 has no name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(SetSignals(), AddComponents(tt.components...)).Run(context.Background())
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRunStartFailureStopsStarted(t *testing.T) {
+	r := &recorder{}
+	failing := Component{
+		Name:      "http",
+		DependsOn: []string{"db"},
+		Start: func(context.Context) error {
+			return errors.New("port in use")
+		},
+	}
+
+	a := New(SetSignals(), AddComponents(recorded(r, "logger"), recorded(r, "db", "logger"), failing))
+	err := a.Run(context.Background())
+	assert.ErrorContains(t, err, "failed to start http: port in use")
+	assert.Equal(t, []string{"start logger", "start db", "stop db", "stop logger"}, r.get())
+}
+
+func TestRunErrorShutsDown(t *testing.T) {
+	r := &recorder{}
+	worker := Component{
+		Name:      "worker",
+		DependsOn: []string{"db"},
+		Run: func(context.Context) error {
+			return errors.New("broker unavailable")
+		},
+	}
+	consumer := Component{
+		Name: "consumer",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			r.add("consumer done")
+			return ctx.Err()
+		},
+	}
+
+	a := New(SetSignals(), AddComponents(recorded(r, "db"), worker, consumer))
+	err := a.Run(context.Background())
+	assert.ErrorContains(t, err, "worker stopped: broker unavailable")
+	assert.Equal(t, []string{"start db", "consumer done", "stop db"}, r.get())
+}
+
+func TestRunStopTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	stuck := Component{
+		Name: "stuck",
+		Run: func(context.Context) error {
+			<-release
+			return nil
+		},
+	}
+
+	a := New(SetSignals(), SetShutdownTimeout(10*time.Millisecond), AddComponents(stuck))
+	err := a.Run(ctx)
+	assert.ErrorContains(t, err, "failed to stop stuck")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	a := New(AddComponents(Component{Name: "a", StopTimeout: time.Second}, Component{Name: "b", StopTimeout: 5 * time.Second}))
+	assert.Equal(t, 5*time.Second, a.shutdownTimeout())
+
+	a.ShutdownTimeout = time.Minute
+	assert.Equal(t, time.Minute, a.shutdownTimeout())
+
+	assert.Equal(t, defaultShutdownTimeout, New().shutdownTimeout())
+}
+
+type signalSubscriber struct{}
+
+func (signalSubscriber) Read(func(context.Context, string, []byte) error) {}
+
+func (signalSubscriber) Close() {}
+
+func TestSubscriberComponentWithoutContext(t *testing.T) {
+	component := SubscriberComponent("orders-consumer", signalSubscriber{}, nil)
+
+	assert.ErrorContains(t, component.Run(context.Background()), "does not implement kafka.ContextSubscriber")
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anthanhphan/saturday/db/postgres"
+	"github.com/anthanhphan/saturday/http/server"
+	"github.com/anthanhphan/saturday/kafka"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/anthanhphan/saturday/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// LoggerComponent returns a component that initialises the global zap logger on
+// start, and syncs and restores the previous logger on stop. Register it first
+// so the other components log through it.
+//
+// Parameters:
+//   - cfg: Logger configuration
+//
+// Returns:
+//   - Component: The "logger" component
+func LoggerComponent(cfg *logger.Config) Component {
+	var (
+		log  *zap.Logger
+		undo func()
+	)
+
+	return Component{
+		Name: "logger",
+		Start: func(context.Context) error {
+			log, undo = logger.InitLogger(cfg)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			_ = log.Sync()
+			undo()
+			return nil
+		},
+	}
+}
+
+// TracingComponent returns a component that sets up the global tracer provider
+// on start, and flushes pending spans and restores the previous provider on stop.
+//
+// Parameters:
+//   - cfg: Tracer configuration
+//   - exporters: Span exporters
+//
+// Returns:
+//   - Component: The "tracing" component
+//
+// Example:
+//
+//	app.TracingComponent(&tracing.Config{ServiceName: "orders"}, exporter)
+func TracingComponent(cfg *tracing.Config, exporters ...sdktrace.SpanExporter) Component {
+	var (
+		provider *sdktrace.TracerProvider
+		undo     func()
+	)
+
+	return Component{
+		Name: "tracing",
+		Start: func(context.Context) error {
+			provider, undo = tracing.InitTracer(cfg, exporters...)
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			defer undo()
+			return provider.Shutdown(ctx)
+		},
+	}
+}
+
+// DatabaseComponent returns a component that opens a database on start, hands
+// it to use, and closes its connection pool on stop.
+//
+// Parameters:
+//   - name: Component name
+//   - open: Function opening the database (e.g. models.Postgres.NewDatabase)
+//   - use: Function receiving the opened database
+//   - dependsOn: Names of components that must start first
+//
+// Returns:
+//   - Component: The database component
+//
+// Example:
+//
+//	var db *postgres.Database
+//	app.DatabaseComponent("postgres", cfg.Postgres.NewDatabase, func(d *postgres.Database) { db = d }, "logger")
+func DatabaseComponent(name string, open func() (*postgres.Database, error), use func(*postgres.Database), dependsOn ...string) Component {
+	var db *postgres.Database
+
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(context.Context) error {
+			var err error
+			if db, err = open(); err != nil {
+				return err
+			}
+			if use != nil {
+				use(db)
+			}
+			return nil
+		},
+		Stop: func(context.Context) error {
+			return db.Close()
+		},
+	}
+}
+
+// SubscriberComponent returns a component that consumes Kafka messages until
+// the app shuts down. The subscriber must implement kafka.ContextSubscriber, so
+// it installs no signal handler of its own; running the component fails otherwise.
+//
+// Parameters:
+//   - name: Component name
+//   - subscriber: The Kafka subscriber
+//   - callback: Function handling every message
+//   - dependsOn: Names of components that must start first
+//
+// Returns:
+//   - Component: The subscriber component
+func SubscriberComponent(name string, subscriber kafka.ISubscriber, callback func(context.Context, string, []byte) error, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(ctx context.Context) error {
+			sub, ok := subscriber.(kafka.ContextSubscriber)
+			if !ok {
+				return fmt.Errorf("subscriber %T does not implement kafka.ContextSubscriber", subscriber)
+			}
+			sub.ReadContext(ctx, callback)
+			return nil
+		},
+	}
+}
+
+// PublisherComponent returns a component that closes a Kafka publisher on stop,
+// after every component depending on it has stopped.
+//
+// Parameters:
+//   - name: Component name
+//   - publisher: The Kafka publisher
+//   - dependsOn: Names of components that must start first
+//
+// Returns:
+//   - Component: The publisher component
+func PublisherComponent(name string, publisher kafka.IPublisher, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Stop: func(context.Context) error {
+			publisher.Close()
+			return nil
+		},
+	}
+}
+
+// HttpServerComponent returns a component that serves srv until the app shuts
+// down, then stops it gracefully within srv.GracefulShutdownTimeout and calls
+// srv.OnCloseFunc.
+//
+// Parameters:
+//   - srv: The HTTP server
+//   - dependsOn: Names of components that must start first
+//
+// Returns:
+//   - Component: The component, named after srv.Name (or "http-server")
+func HttpServerComponent(srv *server.HttpServer, dependsOn ...string) Component {
+	name := srv.Name
+	if name == "" {
+		name = "http-server"
+	}
+
+	return Component{
+		Name:        name,
+		DependsOn:   dependsOn,
+		StopTimeout: srv.GracefulShutdownTimeout,
+		Run:         srv.Start,
+		Stop:        srv.Shutdown,
+	}
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Component is a part of the application with lifecycle hooks. Every hook is optional.
+//
+// Fields:
+//   - Name: Unique component name, referenced by DependsOn
+//   - DependsOn: Names of components that must start before this one and stop after it
+//   - Start: Initialises the component; must not block
+//   - Run: Long-running work (e.g. serving requests); runs in its own goroutine
+//     until its context is cancelled. Returning an error shuts the app down
+//   - Stop: Releases the component's resources within the shutdown deadline
+//   - StopTimeout: Time the component needs to stop, used when the app has no explicit timeout
+type Component struct {
+	Name        string
+	DependsOn   []string
+	Start       func(ctx context.Context) error
+	Run         func(ctx context.Context) error
+	Stop        func(ctx context.Context) error
+	StopTimeout time.Duration
+}
+
+type runningComponent struct {
+	Component
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startComponent calls the Start hook and launches the Run hook, if any.
+// Errors returned by Run are sent to runErrs.
+func startComponent(ctx context.Context, component Component, runErrs chan<- error) (*runningComponent, error) {
+	if component.Start != nil {
+		if err := component.Start(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	running := &runningComponent{
+		Component: component,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+
+	if component.Run == nil {
+		close(running.done)
+		return running, nil
+	}
+
+	go func() {
+		defer close(running.done)
+		if err := component.Run(runCtx); err != nil && !errors.Is(err, context.Canceled) {
+			runErrs <- fmt.Errorf("%s stopped: %w", component.Name, err)
+		}
+	}()
+
+	return running, nil
+}
+
+// stop cancels the Run hook, calls the Stop hook and waits for Run to return.
+func (c *runningComponent) stop(ctx context.Context) error {
+	c.cancel()
+
+	var err error
+	if c.Stop != nil {
+		err = c.Stop(ctx)
+	}
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		err = errors.Join(err, fmt.Errorf("timed out waiting for run to return: %w", ctx.Err()))
+	}
+
+	return err
+}
+
+// sortComponents orders components so that every component comes after its
+// dependencies, keeping registration order otherwise.
+func sortComponents(components []Component) ([]Component, error) {
+	index := make(map[string]int, len(components))
+	for i, component := range components {
+		if component.Name == "" {
+			return nil, fmt.Errorf("component %d has no name", i)
+		}
+		if _, ok := index[component.Name]; ok {
+			return nil, fmt.Errorf("duplicate component: %s", component.Name)
+		}
+		index[component.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(components))
+	ordered := make([]Component, 0, len(components))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, components[i].Name), " -> "))
+		}
+
+		state[i] = visiting
+		for _, dep := range components[i].DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return fmt.Errorf("component %s depends on unknown component %s", components[i].Name, dep)
+			}
+			if err := visit(j, append(path[:len(path):len(path)], components[i].Name)); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		ordered = append(ordered, components[i])
+		return nil
+	}
+
+	for i := range components {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
@@ -1,24 +1,42 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
-
-	"gopkg.in/yaml.v2"
+	"reflect"
+	"strings"
 )
 
 // NewConfig reads and parses a configuration file into a provided struct type.
-// It supports both JSON (.json) and YAML (.yml, .yaml) file formats, automatically
-// detecting the format based on the file extension.
+// It supports JSON (.json), YAML (.yml, .yaml), TOML (.toml) and dotenv (.env)
+// file formats, automatically detecting the format based on the file extension.
+// Other formats can be added with RegisterDecoder.
 //
 // The function uses generics to support any struct type that can be unmarshalled
-// from JSON or YAML.
+// from JSON or YAML. TOML and custom formats are matched against the yaml tags
+// of the struct, and dotenv files use the same variable names as WithEnv.
+//
+// Configuration can be layered through options. Later layers override earlier ones:
+//  1. Values already set on configModel, then `default:"..."` tags for fields still zero
+//  2. The base file (WithBaseFile)
+//  3. The file at configPath, usually obtained from LookupConfigPath
+//  4. Environment variables (WithEnv)
+//
+// Nested structs are merged field by field, while slices are replaced as a whole.
+// Secret fields written as placeholders, such as "file:///run/secrets/db_pw" or
+// "env:DB_PASSWORD", are then replaced by the secret they reference (see
+// RegisterResolver); other string fields are never resolved.
+// The result is checked against its `validate:"..."` tags (see Validate) and every
+// invalid field is reported at once.
 //
 // Parameters:
 //   - configPath: string - Path to the configuration file
 //   - configModel: *T - Pointer to the struct that will hold the configuration
+//   - opts: ...Option - Optional loading options
 //
 // Returns:
 //   - *T - Pointer to the populated configuration struct
@@ -27,53 +45,187 @@ import (
 // Example:
 //
 //	type MyConfig struct {
-//	    Host string `json:"host" yaml:"host"`
-//	    Port int    `json:"port" yaml:"port"`
+//	    Host     string `json:"host" yaml:"host" default:"localhost"`
+//	    Port     int    `json:"port" yaml:"port" validate:"required"`
+//	    Password Secret `json:"password" yaml:"password" env:"DB_PASSWORD"`
 //	}
 //
-//	config, err := NewConfig("config.yaml", &MyConfig{})
+//	path, err := LookupConfigPath("staging")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config, err := NewConfig(path, &MyConfig{},
+//	    WithBaseFile("./env/env.base.json"),
+//	    WithEnv("APP"), // APP_HOST, APP_PORT and DB_PASSWORD override the files
+//	)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("Host: %s, Port: %d\n", config.Host, config.Port)
-func NewConfig[T any](configPath string, configModel *T) (*T, error) {
+func NewConfig[T any](configPath string, configModel *T, opts ...Option) (*T, error) {
+	if configPath == "" {
+		return nil, fmt.Errorf("config path is required")
+	}
+
+	format, err := formatFromExt(filepath.Ext(configPath))
+	if err != nil {
+		return nil, err
+	}
+
+	return load(configModel, newOptions(opts), os.ReadFile, func() ([]byte, string, error) {
+		// Read the file content.
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read config file: %w", err)
+		}
+		return data, format, nil
+	})
+}
+
+// NewConfigFromReader parses configuration read from r in an explicit format,
+// applying the same layering, secret resolution and validation as NewConfig.
+// A base file set through WithBaseFile is read from disk.
+//
+// Parameters:
+//   - r: io.Reader - Source of the configuration content
+//   - format: string - Format name or extension, e.g. "json", "yaml", ".toml", "env"
+//   - configModel: *T - Pointer to the struct that will hold the configuration
+//   - opts: ...Option - Optional loading options
+//
+// Returns:
+//   - *T - Pointer to the populated configuration struct
+//   - error - Error if any occurred during reading or parsing
+//
+// Example:
+//
+//	cfg, err := NewConfigFromReader(strings.NewReader("host = \"localhost\""), "toml", &MyConfig{})
+func NewConfigFromReader[T any](r io.Reader, format string, configModel *T, opts ...Option) (*T, error) {
+	format, err := formatFromExt("." + strings.TrimPrefix(format, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	return load(configModel, newOptions(opts), os.ReadFile, func() ([]byte, string, error) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read config: %w", err)
+		}
+		return data, format, nil
+	})
+}
+
+// NewConfigFromFS reads and parses a configuration file from fsys, which makes
+// it possible to bundle configuration into the binary with embed.FS. The format
+// is detected from the file extension and a base file set through WithBaseFile
+// is read from fsys as well.
+//
+// Parameters:
+//   - fsys: fs.FS - File system holding the configuration files
+//   - configPath: string - Path of the configuration file within fsys
+//   - configModel: *T - Pointer to the struct that will hold the configuration
+//   - opts: ...Option - Optional loading options
+//
+// Returns:
+//   - *T - Pointer to the populated configuration struct
+//   - error - Error if any occurred during reading or parsing
+//
+// Example:
+//
+//	//go:embed env
+//	var envFS embed.FS
+//
+//	cfg, err := NewConfigFromFS(envFS, "env/env.production.yaml", &MyConfig{})
+func NewConfigFromFS[T any](fsys fs.FS, configPath string, configModel *T, opts ...Option) (*T, error) {
 	if configPath == "" {
 		return nil, fmt.Errorf("config path is required")
 	}
 
-	// Read the file content.
-	data, err := os.ReadFile(configPath)
+	format, err := formatFromExt(path.Ext(configPath))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
 	}
 
-	// Determine the file format based on the file extension.
-	switch ext := filepath.Ext(configPath); ext {
-	case ".json":
-		// Unmarshal JSON content
-		if err := json.Unmarshal(data, configModel); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	return load(configModel, newOptions(opts), readFile, func() ([]byte, string, error) {
+		data, err := readFile(configPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read config file: %w", err)
 		}
-	case ".yml", ".yaml":
-		// Unmarshal YAML content
-		if err := yaml.Unmarshal(data, configModel); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		return data, format, nil
+	})
+}
+
+// load applies every configuration layer to configModel. readFile is used for
+// the base file, while readMain returns the main configuration and its format.
+func load[T any](configModel *T, o *options, readFile func(string) ([]byte, error), readMain func() ([]byte, string, error)) (*T, error) {
+	modelType := reflect.TypeOf(configModel).Elem()
+
+	sources := Sources{}
+	collectDefaults(modelType, "", sources)
+
+	if err := applyDefaults(reflect.ValueOf(configModel), ""); err != nil {
+		return nil, err
+	}
+
+	if o.baseFile != "" {
+		format, err := formatFromExt(filepath.Ext(o.baseFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load base config: %w", err)
+		}
+
+		data, err := readFile(o.baseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load base config: failed to read config file: %w", err)
+		}
+
+		if err := decodeLayer(data, format, configModel, o, SourceBase, sources); err != nil {
+			return nil, fmt.Errorf("failed to load base config: %w", err)
 		}
-	default:
-		return nil, fmt.Errorf("unsupported file extension: %s", ext)
+	}
+
+	data, format, err := readMain()
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeLayer(data, format, configModel, o, SourceFile, sources); err != nil {
+		return nil, err
+	}
+
+	if o.envEnable {
+		if err := applyEnv(reflect.ValueOf(configModel), o.envPrefix, o.lookupEnv, "", SourceEnv, sources); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := resolveSecrets(reflect.ValueOf(configModel), resolversFor(o), ""); err != nil {
+		return nil, err
+	}
+
+	if err := Validate(configModel); err != nil {
+		return nil, err
+	}
+
+	if o.sources != nil {
+		*o.sources = sources
 	}
 
 	return configModel, nil
 }
 
 // GetConfigPath constructs the path to a configuration file based on the environment
-// and desired file extension. It follows a standardized file naming convention:
+// and desired file extension. By default it follows a standardized file naming convention:
 // "./env/env.<environment>.<extension>".
 //
-// The function supports multiple environments and will fall back to a local configuration
-// if an unknown environment is specified.
+// The environments, directories and naming template come from the default
+// Registry, which can be replaced with SetDefaultRegistry. The default registry
+// falls back to a local configuration if an unknown environment is specified.
+// A strict registry yields an empty path instead; use LookupConfigPath to get
+// the "unknown environment" error.
 //
-// Supported environments:
+// Supported environments (default registry):
 //   - "qc" -> Quality Control environment
 //   - "staging" -> Staging environment
 //   - "production" -> Production environment
@@ -89,25 +241,9 @@ func NewConfig[T any](configPath string, configModel *T) (*T, error) {
 // Example:
 //
 //	path := GetConfigPath("staging", "json")  // Returns "./env/env.staging.json"
-//	path := GetConfigPath("production")       // Returns "./env/env.production.yml"
-//	path := GetConfigPath("unknown")          // Returns "./env/env.local.yml"
+//	path := GetConfigPath("production")       // Returns "./env/env.production.json"
+//	path := GetConfigPath("unknown", "yml")   // Returns "./env/env.local.yml"
 func GetConfigPath(env string, ext ...string) string {
-	fileExt := "json"
-	if len(ext) != 0 {
-		fileExt = ext[0]
-	}
-
-	configPaths := map[string]string{
-		"qc":         "./env/env.qc." + fileExt,
-		"staging":    "./env/env.staging." + fileExt,
-		"production": "./env/env.production." + fileExt,
-	}
-
-	// Return the config path for the environment or the default local file.
-	if path, exists := configPaths[env]; exists {
-		return path
-	}
-
-	// Default to a local configuration file based on the provided extension.
-	return "./env/env.local." + fileExt
+	path, _ := defaultRegistry.Load().Path(env, ext...)
+	return path
 }
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Host    string        `json:"host" yaml:"host"`
+	Port    int64         `json:"port" yaml:"port"`
+	Addrs   []string      `json:"addrs" yaml:"addrs"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	Secret  string        `json:"secret" yaml:"secret" env:"TEST_SECRET"`
+	Acl     struct {
+		Enable   bool   `json:"enable" yaml:"enable"`
+		User     string `json:"user" yaml:"user"`
+		Password string `json:"password" yaml:"password"`
+	}
+	Consumer struct {
+		ReturnErrors *bool `json:"return_errors" yaml:"return_errors"`
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func mapLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Run("json file", func(t *testing.T) {
+		path := writeFile(t, "config.json", `{"host":"localhost","port":8080,"addrs":["a","b"]}`)
+
+		cfg, err := NewConfig(path, &testConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", cfg.Host)
+		assert.Equal(t, int64(8080), cfg.Port)
+		assert.Equal(t, []string{"a", "b"}, cfg.Addrs)
+	})
+
+	t.Run("yaml file", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "host: localhost\nport: 8080\nacl:\n  user: admin\n")
+
+		cfg, err := NewConfig(path, &testConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", cfg.Host)
+		assert.Equal(t, "admin", cfg.Acl.User)
+	})
+
+	t.Run("empty path", func(t *testing.T) {
+		_, err := NewConfig("", &testConfig{})
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		path := writeFile(t, "config.txt", "host=localhost")
+
+		_, err := NewConfig(path, &testConfig{})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewConfigLayers(t *testing.T) {
+	base := writeFile(t, "base.yaml", "host: base-host\nport: 1000\naddrs: [base1, base2]\nacl:\n  enable: true\n  user: base-user\n")
+	file := writeFile(t, "env.yaml", "port: 2000\nacl:\n  user: file-user\n")
+	env := map[string]string{
+		"APP_ADDRS":                  "env1, env2",
+		"APP_ACL_PASSWORD":           "secret",
+		"APP_TIMEOUT":                "1m30s",
+		"APP_CONSUMER_RETURN_ERRORS": "true",
+		"TEST_SECRET":                "tagged",
+		"APP_SECRET":                 "ignored",
+	}
+
+	var sources Sources
+	cfg, err := NewConfig(file, &testConfig{Host: "default-host"},
+		WithBaseFile(base),
+		WithEnv("APP"),
+		WithLookupEnv(mapLookup(env)),
+		WithSources(&sources),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "base-host", cfg.Host)
+	assert.Equal(t, int64(2000), cfg.Port)
+	assert.Equal(t, []string{"env1", "env2"}, cfg.Addrs)
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+	assert.Equal(t, "tagged", cfg.Secret)
+	assert.True(t, cfg.Acl.Enable)
+	assert.Equal(t, "file-user", cfg.Acl.User)
+	assert.Equal(t, "secret", cfg.Acl.Password)
+	require.NotNil(t, cfg.Consumer.ReturnErrors)
+	assert.True(t, *cfg.Consumer.ReturnErrors)
+
+	assert.Equal(t, Sources{
+		"host":                   SourceBase,
+		"port":                   SourceFile,
+		"addrs":                  SourceEnv,
+		"timeout":                SourceEnv,
+		"secret":                 SourceEnv,
+		"acl.enable":             SourceBase,
+		"acl.user":               SourceFile,
+		"acl.password":           SourceEnv,
+		"consumer.return_errors": SourceEnv,
+	}, sources)
+}
+
+func TestNewConfigEnvError(t *testing.T) {
+	path := writeFile(t, "config.json", `{"host":"localhost"}`)
+
+	_, err := NewConfig(path, &testConfig{},
+		WithEnv("APP"),
+		WithLookupEnv(mapLookup(map[string]string{"APP_PORT": "not-a-number"})),
+	)
+	assert.ErrorContains(t, err, "APP_PORT")
+}
+
+func TestGetConfigPath(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		ext  []string
+		want string
+	}{
+		{"staging json", "staging", []string{"json"}, "./env/env.staging.json"},
+		{"production default ext", "production", nil, "./env/env.production.json"},
+		{"unknown falls back to local", "unknown", []string{"yml"}, "./env/env.local.yml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetConfigPath(tt.env, tt.ext...))
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// RegistryOption is a function type that modifies Registry configuration.
+type RegistryOption func(*Registry)
+
+// Registry describes the known environments and where their configuration
+// files live. File names are built from a template in which "{env}" and "{ext}"
+// are replaced by the environment name and the file extension.
+//
+// Fields:
+//   - environments: Known environment names
+//   - dirs: Directories searched in order for the configuration file
+//   - template: File name template (e.g. "env.{env}.{ext}")
+//   - defaultExt: Extension used when none is given
+//   - fallback: Environment used for unknown names when not strict
+//   - strict: Whether unknown environments are rejected
+type Registry struct {
+	environments map[string]struct{}
+	dirs         []string
+	template     string
+	defaultExt   string
+	fallback     string
+	strict       bool
+}
+
+var defaultRegistry atomic.Pointer[Registry]
+
+func init() {
+	defaultRegistry.Store(NewRegistry())
+}
+
+// NewRegistry creates an environment registry. Without options it matches the
+// historical GetConfigPath behaviour: "qc", "staging" and "production" map to
+// "./env/env.<env>.<ext>", "json" is the default extension, and unknown
+// environments fall back to "local".
+//
+// Parameters:
+//   - options: Variable number of RegistryOption functions to configure the registry
+//
+// Returns:
+//   - *Registry: A new configured registry
+//
+// Example:
+//
+//	registry := NewRegistry(
+//	    WithEnvironments("dev", "uat", "production-eu", "production-us"),
+//	    WithSearchDirs(".", ExecutableDir(), "/etc/order-service"),
+//	    WithFileTemplate("{env}.{ext}"),
+//	    WithStrict(true),
+//	)
+//	path, err := registry.Resolve("uat", "yaml") // e.g. "/etc/order-service/uat.yaml"
+func NewRegistry(options ...RegistryOption) *Registry {
+	r := &Registry{
+		environments: map[string]struct{}{},
+		dirs:         []string{"./env"},
+		template:     "env.{env}.{ext}",
+		defaultExt:   "json",
+		fallback:     "local",
+	}
+
+	WithEnvironments("qc", "staging", "production")(r)
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// WithEnvironments returns a RegistryOption that registers additional environments.
+//
+// Parameters:
+//   - names: Environment names to register
+//
+// Returns:
+//   - RegistryOption: Function that adds the environments
+func WithEnvironments(names ...string) RegistryOption {
+	return func(r *Registry) {
+		for _, name := range names {
+			r.environments[name] = struct{}{}
+		}
+	}
+}
+
+// WithOnlyEnvironments returns a RegistryOption that replaces the default
+// environments with the given ones.
+//
+// Parameters:
+//   - names: The complete list of environment names
+//
+// Returns:
+//   - RegistryOption: Function that sets the environments
+func WithOnlyEnvironments(names ...string) RegistryOption {
+	return func(r *Registry) {
+		r.environments = map[string]struct{}{}
+		WithEnvironments(names...)(r)
+	}
+}
+
+// WithSearchDirs returns a RegistryOption that sets the directories searched,
+// in order, for a configuration file. Empty entries are ignored.
+//
+// Parameters:
+//   - dirs: Directories to search (e.g. ".", ExecutableDir(), "/etc/<service>")
+//
+// Returns:
+//   - RegistryOption: Function that sets the search directories
+func WithSearchDirs(dirs ...string) RegistryOption {
+	return func(r *Registry) {
+		r.dirs = r.dirs[:0:0]
+		for _, dir := range dirs {
+			if dir != "" {
+				r.dirs = append(r.dirs, dir)
+			}
+		}
+	}
+}
+
+// WithFileTemplate returns a RegistryOption that sets the file name template.
+//
+// Parameters:
+//   - template: Template containing "{env}" and optionally "{ext}"
+//
+// Returns:
+//   - RegistryOption: Function that sets the template
+func WithFileTemplate(template string) RegistryOption {
+	return func(r *Registry) {
+		r.template = template
+	}
+}
+
+// WithDefaultExt returns a RegistryOption that sets the extension used when none is given.
+//
+// Parameters:
+//   - ext: The default extension, with or without a leading dot
+//
+// Returns:
+//   - RegistryOption: Function that sets the default extension
+func WithDefaultExt(ext string) RegistryOption {
+	return func(r *Registry) {
+		r.defaultExt = strings.TrimPrefix(ext, ".")
+	}
+}
+
+// WithFallback returns a RegistryOption that sets the environment used for
+// unknown names when the registry is not strict.
+//
+// Parameters:
+//   - env: The fallback environment name
+//
+// Returns:
+//   - RegistryOption: Function that sets the fallback
+func WithFallback(env string) RegistryOption {
+	return func(r *Registry) {
+		r.fallback = env
+	}
+}
+
+// WithStrict returns a RegistryOption that makes Path and Resolve return an
+// error for unknown environments instead of silently using the fallback. The
+// fallback itself is then only accepted if it is registered.
+//
+// Parameters:
+//   - strict: Whether unknown environments are rejected
+//
+// Returns:
+//   - RegistryOption: Function that sets strict mode
+func WithStrict(strict bool) RegistryOption {
+	return func(r *Registry) {
+		r.strict = strict
+	}
+}
+
+// ExecutableDir returns the directory of the running executable, or an empty
+// string if it cannot be determined. It is meant to be passed to WithSearchDirs.
+//
+// Returns:
+//   - string: The executable's directory
+func ExecutableDir() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(exe)
+}
+
+// SetDefaultRegistry replaces the registry used by GetConfigPath, LookupConfigPath
+// and ResolveConfigPath.
+//
+// Parameters:
+//   - registry: The registry to use; nil restores the default one
+//
+// Example:
+//
+//	config.SetDefaultRegistry(config.NewRegistry(config.WithEnvironments("dev", "uat")))
+//	path := config.GetConfigPath("uat") // "./env/env.uat.json"
+func SetDefaultRegistry(registry *Registry) {
+	if registry == nil {
+		registry = NewRegistry()
+	}
+	defaultRegistry.Store(registry)
+}
+
+// Environments returns the registered environment names.
+//
+// Returns:
+//   - []string: The known environment names in no particular order
+func (r *Registry) Environments() []string {
+	names := make([]string, 0, len(r.environments))
+	for name := range r.environments {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Resolve returns the path of the configuration file for an environment.
+// Every search directory is tried in order and the first existing file wins.
+//
+// Parameters:
+//   - env: The environment name
+//   - ext: Optional file extension (defaults to the registry's default extension)
+//
+// Returns:
+//   - string: The path of the configuration file
+//   - error: Error if the environment is unknown in strict mode, or no file exists
+//
+// Example:
+//
+//	path, err := registry.Resolve("staging", "yaml")
+func (r *Registry) Resolve(env string, ext ...string) (string, error) {
+	env, err := r.environment(env)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := r.candidates(env, ext...)
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("config file for environment %q not found, searched: %s", env, strings.Join(candidates, ", "))
+}
+
+// Path returns the path of the configuration file for an environment without
+// checking that it exists when there is a single search directory. With several
+// directories the first existing file wins, defaulting to the first directory.
+// Unknown environments use the fallback, or are rejected in strict mode.
+//
+// Parameters:
+//   - env: The environment name
+//   - ext: Optional file extension (defaults to the registry's default extension)
+//
+// Returns:
+//   - string: The path of the configuration file
+//   - error: Error if the environment is unknown in strict mode
+//
+// Example:
+//
+//	path, err := registry.Path("staging", "yaml")
+func (r *Registry) Path(env string, ext ...string) (string, error) {
+	env, err := r.environment(env)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := r.candidates(env, ext...)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no search directory for environment %q", env)
+	}
+	if len(candidates) > 1 {
+		for _, candidate := range candidates {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+	}
+	return candidates[0], nil
+}
+
+// environment validates env and applies the fallback for unknown names. In
+// strict mode only registered environments are accepted.
+func (r *Registry) environment(env string) (string, error) {
+	if _, ok := r.environments[env]; ok {
+		return env, nil
+	}
+	if r.strict {
+		return "", fmt.Errorf("unknown environment %q", env)
+	}
+	return r.fallback, nil
+}
+
+// candidates returns the possible file paths for env, one per search directory.
+func (r *Registry) candidates(env string, ext ...string) []string {
+	fileExt := r.defaultExt
+	if len(ext) != 0 {
+		fileExt = strings.TrimPrefix(ext[0], ".")
+	}
+
+	name := strings.NewReplacer("{env}", env, "{ext}", fileExt).Replace(r.template)
+
+	candidates := make([]string, 0, len(r.dirs))
+	for _, dir := range r.dirs {
+		if dir == "." || dir == "./" {
+			// Keep the historical "./" prefix, which filepath.Join would drop.
+			candidates = append(candidates, "./"+name)
+			continue
+		}
+		candidates = append(candidates, joinDir(dir, name))
+	}
+	return candidates
+}
+
+// joinDir joins dir and name while preserving a leading "./" in dir.
+func joinDir(dir, name string) string {
+	path := filepath.Join(dir, name)
+	if strings.HasPrefix(dir, "./") && !strings.HasPrefix(path, "./") {
+		return "./" + path
+	}
+	return path
+}
+
+// LookupConfigPath returns the path of the configuration file for an
+// environment using the default registry (see SetDefaultRegistry and
+// Registry.Path). Unlike GetConfigPath it reports unknown environments of a
+// strict registry.
+//
+// Parameters:
+//   - env: The environment name
+//   - ext: Optional file extension
+//
+// Returns:
+//   - string: The path of the configuration file
+//   - error: Error if the environment is unknown in strict mode
+//
+// Example:
+//
+//	path, err := config.LookupConfigPath(os.Getenv("APP_ENV"), "yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cfg, err := config.NewConfig(path, &models.App{})
+func LookupConfigPath(env string, ext ...string) (string, error) {
+	return defaultRegistry.Load().Path(env, ext...)
+}
+
+// ResolveConfigPath resolves the configuration file for an environment using
+// the default registry (see SetDefaultRegistry and Registry.Resolve).
+//
+// Parameters:
+//   - env: The environment name
+//   - ext: Optional file extension
+//
+// Returns:
+//   - string: The path of an existing configuration file
+//   - error: Error if the environment is unknown in strict mode, or no file exists
+func ResolveConfigPath(env string, ext ...string) (string, error) {
+	return defaultRegistry.Load().Resolve(env, ext...)
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryPath(t *testing.T) {
+	registry := NewRegistry(
+		WithOnlyEnvironments("dev", "uat", "production-eu"),
+		WithSearchDirs("/etc/service"),
+		WithFileTemplate("{env}.{ext}"),
+		WithDefaultExt(".yaml"),
+		WithFallback("dev"),
+	)
+
+	tests := []struct {
+		name string
+		env  string
+		ext  []string
+		want string
+	}{
+		{"registered environment", "uat", nil, "/etc/service/uat.yaml"},
+		{"explicit extension", "production-eu", []string{"toml"}, "/etc/service/production-eu.toml"},
+		{"unknown falls back", "staging", nil, "/etc/service/dev.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := registry.Path(tt.env, tt.ext...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, path)
+		})
+	}
+}
+
+func TestRegistryStrict(t *testing.T) {
+	registry := NewRegistry(WithStrict(true))
+
+	_, err := registry.Resolve("unknown")
+	assert.EqualError(t, err, `unknown environment "unknown"`)
+
+	_, err = registry.Path("unknown")
+	assert.EqualError(t, err, `unknown environment "unknown"`)
+
+	// The fallback is not accepted unless registered
+	_, err = registry.Path("local")
+	assert.EqualError(t, err, `unknown environment "local"`)
+
+	path, err := NewRegistry(WithStrict(true), WithEnvironments("local")).Path("local")
+	require.NoError(t, err)
+	assert.Equal(t, "./env/env.local.json", path)
+}
+
+func TestRegistryResolveSearchDirs(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(second, "env.staging.yaml"), []byte("host: second\n"), 0o600))
+
+	registry := NewRegistry(WithSearchDirs(first, "", second))
+
+	path, err := registry.Resolve("staging", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(second, "env.staging.yaml"), path)
+	guessed, err := registry.Path("staging", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, path, guessed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(first, "env.staging.yaml"), []byte("host: first\n"), 0o600))
+	path, err = registry.Resolve("staging", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(first, "env.staging.yaml"), path)
+
+	_, err = registry.Resolve("production", "yaml")
+	assert.ErrorContains(t, err, "not found")
+	guessed, err = registry.Path("production", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(first, "env.production.yaml"), guessed)
+}
+
+func TestSetDefaultRegistry(t *testing.T) {
+	SetDefaultRegistry(NewRegistry(WithEnvironments("dev")))
+	defer SetDefaultRegistry(nil)
+
+	assert.Equal(t, "./env/env.dev.json", GetConfigPath("dev"))
+	assert.Equal(t, "./env/env.staging.json", GetConfigPath("staging"))
+
+	SetDefaultRegistry(NewRegistry(WithStrict(true)))
+	assert.Equal(t, "", GetConfigPath("dev"))
+	_, err := LookupConfigPath("dev")
+	assert.EqualError(t, err, `unknown environment "dev"`)
+
+	path, err := LookupConfigPath("qc", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "./env/env.qc.yaml", path)
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	formatJSON   = "json"
+	formatYAML   = "yaml"
+	formatTOML   = "toml"
+	formatDotenv = "env"
+)
+
+// Decoder decodes configuration data into a generic document. Keys of the
+// document are matched against the yaml tags of the model (or its lower-cased
+// field names), so one model works with every format.
+type Decoder func(data []byte) (map[string]any, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		formatTOML: decodeTOML,
+	}
+)
+
+// RegisterDecoder registers a Decoder for a file extension. Registered decoders
+// take precedence over the built-in formats.
+//
+// Parameters:
+//   - ext: The file extension, with or without a leading dot (e.g. "hcl")
+//   - decoder: The decoder for that extension
+//
+// Example:
+//
+//	config.RegisterDecoder("ini", func(data []byte) (map[string]any, error) {
+//	    return parseIni(data)
+//	})
+//	cfg, err := config.NewConfig("./env/env.local.ini", &MyConfig{})
+func RegisterDecoder(ext string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.TrimPrefix(ext, ".")] = decoder
+}
+
+// getDecoder returns the registered decoder for format, if any.
+func getDecoder(format string) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	decoder, ok := decoders[format]
+	return decoder, ok
+}
+
+// formatFromExt determines the file format based on the file extension.
+func formatFromExt(ext string) (string, error) {
+	format := strings.TrimPrefix(ext, ".")
+	if _, ok := getDecoder(format); ok {
+		return format, nil
+	}
+
+	switch format {
+	case "json":
+		return formatJSON, nil
+	case "yml", "yaml":
+		return formatYAML, nil
+	case "env":
+		return formatDotenv, nil
+	default:
+		return "", fmt.Errorf("unsupported file extension: %s", ext)
+	}
+}
+
+// decodeLayer unmarshals data on top of configModel and records src for every
+// field the data sets.
+func decodeLayer(data []byte, format string, configModel any, o *options, src Source, sources Sources) error {
+	if decoder, ok := getDecoder(format); ok {
+		document, err := decoder(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", format, err)
+		}
+		return decodeDocument(document, configModel, src, sources)
+	}
+
+	switch format {
+	case formatDotenv:
+		values, err := parseDotenv(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse dotenv: %w", err)
+		}
+		lookup := func(key string) (string, bool) {
+			value, ok := values[key]
+			return value, ok
+		}
+		return applyEnv(reflect.ValueOf(configModel), o.envPrefix, lookup, "", src, sources)
+	case formatJSON, formatYAML:
+		if err := unmarshal(format, data, configModel); err != nil {
+			return err
+		}
+
+		// Decode the content generically to find out which fields the file sets.
+		var document any
+		if err := unmarshal(format, data, &document); err != nil {
+			return err
+		}
+		markPresent(reflect.TypeOf(configModel), document, format, "", src, sources)
+		return nil
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// decodeDocument fills configModel from a generic document by round-tripping
+// it through YAML, so the yaml tags of the model apply.
+func decodeDocument(document map[string]any, configModel any, src Source, sources Sources) error {
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, configModel); err != nil {
+		return fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+
+	markPresent(reflect.TypeOf(configModel), document, formatYAML, "", src, sources)
+	return nil
+}
+
+// unmarshal decodes data of the given format into v.
+func unmarshal(format string, data []byte, v any) error {
+	switch format {
+	case formatJSON:
+		// Unmarshal JSON content
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON: %w", err)
+		}
+	case formatYAML:
+		// Unmarshal YAML content
+		if err := yaml.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to unmarshal YAML: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	return nil
+}
+
+// decodeTOML decodes TOML content into a generic document.
+func decodeTOML(data []byte) (map[string]any, error) {
+	document := map[string]any{}
+	if err := toml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// parseDotenv parses KEY=VALUE lines. Blank lines, comments and an optional
+// "export " prefix are ignored. Double-quoted values support \n, \t, \" and \\
+// escapes, single-quoted values are literal, and unquoted values end at " #".
+func parseDotenv(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+
+		values[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
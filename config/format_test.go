@@ -0,0 +1,141 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type formatConfig struct {
+	Host    string        `yaml:"host"`
+	Port    int64         `yaml:"port"`
+	Addrs   []string      `yaml:"addrs"`
+	Timeout time.Duration `yaml:"timeout"`
+	Acl     struct {
+		User     string `yaml:"user"`
+		Password Secret `yaml:"password"`
+	} `yaml:"acl"`
+}
+
+func TestNewConfigTOML(t *testing.T) {
+	path := writeFile(t, "config.toml", `
+host = "localhost"
+port = 5432
+addrs = ["a:9092", "b:9092"]
+timeout = "5s"
+
+[acl]
+user = "admin"
+`)
+
+	var sources Sources
+	cfg, err := NewConfig(path, &formatConfig{}, WithSources(&sources))
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.Host)
+	assert.Equal(t, int64(5432), cfg.Port)
+	assert.Equal(t, []string{"a:9092", "b:9092"}, cfg.Addrs)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, "admin", cfg.Acl.User)
+	assert.Equal(t, SourceFile, sources["acl.user"])
+	assert.Equal(t, SourceDefault, sources["acl.password"])
+}
+
+func TestNewConfigDotenv(t *testing.T) {
+	base := writeFile(t, "base.yaml", "host: base-host\nport: 1\n")
+	path := writeFile(t, ".env", `
+# comment
+export APP_PORT=5432
+APP_ADDRS=a:9092, b:9092 # trailing comment
+APP_ACL_USER='admin # not a comment'
+APP_ACL_PASSWORD="p@ss\"word"
+`)
+
+	var sources Sources
+	cfg, err := NewConfig(path, &formatConfig{},
+		WithBaseFile(base),
+		WithEnv("APP"),
+		WithLookupEnv(mapLookup(map[string]string{"APP_PORT": "6543"})),
+		WithSources(&sources),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "base-host", cfg.Host)
+	assert.Equal(t, int64(6543), cfg.Port)
+	assert.Equal(t, []string{"a:9092", "b:9092"}, cfg.Addrs)
+	assert.Equal(t, "admin # not a comment", cfg.Acl.User)
+	assert.Equal(t, `p@ss"word`, cfg.Acl.Password.Value())
+	assert.Equal(t, SourceBase, sources["host"])
+	assert.Equal(t, SourceEnv, sources["port"])
+	assert.Equal(t, SourceFile, sources["addrs"])
+}
+
+func TestNewConfigFromReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+	}{
+		{"json", "json", `{"host":"localhost","port":80}`},
+		{"yaml with dot", ".yml", "host: localhost\nport: 80\n"},
+		{"toml", "toml", "host = \"localhost\"\nport = 80\n"},
+		{"dotenv", "env", "HOST=localhost\nPORT=80\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewConfigFromReader(strings.NewReader(tt.content), tt.format, &formatConfig{})
+			require.NoError(t, err)
+			assert.Equal(t, "localhost", cfg.Host)
+			assert.Equal(t, int64(80), cfg.Port)
+		})
+	}
+
+	_, err := NewConfigFromReader(strings.NewReader(""), "xml", &formatConfig{})
+	assert.Error(t, err)
+}
+
+func TestNewConfigFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"env/env.base.yaml":       {Data: []byte("host: base-host\nport: 1\n")},
+		"env/env.production.toml": {Data: []byte("port = 443\n")},
+	}
+
+	cfg, err := NewConfigFromFS(fsys, "env/env.production.toml", &formatConfig{}, WithBaseFile("env/env.base.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "base-host", cfg.Host)
+	assert.Equal(t, int64(443), cfg.Port)
+
+	_, err = NewConfigFromFS(fsys, "env/missing.yaml", &formatConfig{})
+	assert.Error(t, err)
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	RegisterDecoder(".kv", func(data []byte) (map[string]any, error) {
+		document := map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			key, value, _ := strings.Cut(line, ":")
+			document[key] = value
+		}
+		return document, nil
+	})
+	defer func() {
+		decodersMu.Lock()
+		delete(decoders, "kv")
+		decodersMu.Unlock()
+	}()
+
+	path := writeFile(t, "config.kv", "host:localhost\ntimeout:1m\n")
+
+	cfg, err := NewConfig(path, &formatConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.Host)
+	assert.Equal(t, time.Minute, cfg.Timeout)
+}
+
+func TestParseDotenvError(t *testing.T) {
+	_, err := parseDotenv([]byte("NOT_A_PAIR\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
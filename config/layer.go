@@ -0,0 +1,324 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthanhphan/saturday/utils"
+)
+
+// Source identifies where the final value of a configuration field came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceBase    Source = "base"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+)
+
+// Sources maps the dotted path of every leaf field (e.g. "consumer.max_processing_time")
+// to the Source that supplied its final value.
+type Sources map[string]Source
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// fieldName returns the name used for a struct field in paths and derived
+// environment variable names. It prefers the yaml tag, then the json tag, and
+// falls back to the lower-cased field name as yaml.v2 does.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"yaml", "json"} {
+		if name := tagName(field.Tag.Get(key)); name != "" && name != "-" {
+			return name
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+// formatKey returns the key a decoder looks for when filling a struct field.
+func formatKey(field reflect.StructField, format string) string {
+	if name := tagName(field.Tag.Get(format)); name != "" {
+		return name
+	}
+	if format == formatYAML {
+		return strings.ToLower(field.Name)
+	}
+	return field.Name
+}
+
+// tagName returns the name part of a struct tag value such as "host,omitempty".
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+// isInline reports whether the fields of an embedded struct are flattened into the parent.
+func isInline(field reflect.StructField) bool {
+	return field.Anonymous && field.Tag.Get("yaml") == "" && field.Tag.Get("json") == ""
+}
+
+// isNested reports whether a field of type t holds nested configuration rather than a single value.
+func isNested(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// joinPath appends a field name to a dotted path.
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// skipField reports whether a field is ignored by the layered loader.
+func skipField(field reflect.StructField) bool {
+	return !field.IsExported() || tagName(field.Tag.Get("yaml")) == "-" || tagName(field.Tag.Get("json")) == "-"
+}
+
+// collectDefaults marks every leaf field of t as coming from SourceDefault.
+func collectDefaults(t reflect.Type, prefix string, sources Sources) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if skipField(field) {
+			continue
+		}
+
+		path := prefix
+		if !isInline(field) {
+			path = joinPath(prefix, fieldName(field))
+		}
+
+		if isNested(field.Type) {
+			collectDefaults(field.Type, path, sources)
+			continue
+		}
+		sources[path] = SourceDefault
+	}
+}
+
+// markPresent walks a generically decoded document alongside the struct type t
+// and records src for every leaf field whose key is present in the document.
+func markPresent(t reflect.Type, node any, format, prefix string, src Source, sources Sources) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if skipField(field) {
+			continue
+		}
+
+		if isInline(field) {
+			markPresent(field.Type, node, format, prefix, src, sources)
+			continue
+		}
+
+		value, ok := lookupKey(node, formatKey(field, format), format == formatJSON)
+		if !ok {
+			continue
+		}
+
+		path := joinPath(prefix, fieldName(field))
+		if isNested(field.Type) {
+			markPresent(field.Type, value, format, path, src, sources)
+			continue
+		}
+		sources[path] = src
+	}
+}
+
+// lookupKey finds key in a decoded mapping. JSON keys are matched
+// case-insensitively to mirror encoding/json.
+func lookupKey(node any, key string, foldCase bool) (any, bool) {
+	match := func(k string) bool {
+		if foldCase {
+			return strings.EqualFold(k, key)
+		}
+		return k == key
+	}
+
+	switch m := node.(type) {
+	case map[string]any:
+		if v, ok := m[key]; ok {
+			return v, true
+		}
+		for k, v := range m {
+			if match(k) {
+				return v, true
+			}
+		}
+	case map[any]any:
+		for k, v := range m {
+			if s, ok := k.(string); ok && match(s) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// envName returns the environment variable that overrides a field.
+func envName(field reflect.StructField, prefix, path string) string {
+	if name := field.Tag.Get("env"); name != "" {
+		return name
+	}
+
+	name := strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+	if prefix == "" {
+		return name
+	}
+	return strings.ToUpper(prefix) + "_" + name
+}
+
+// applyEnv overrides the leaf fields of v with values returned by lookup and
+// records src for each of them. It serves both the environment and dotenv files.
+// Nil nested pointers are only allocated when one of their fields is set.
+func applyEnv(v reflect.Value, envPrefix string, lookup func(string) (string, bool), prefix string, src Source, sources Sources) error {
+	_, err := applyEnvValue(v, envPrefix, lookup, prefix, src, sources)
+	return err
+}
+
+// applyEnvValue implements applyEnv and reports whether any field was set.
+func applyEnvValue(v reflect.Value, envPrefix string, lookup func(string) (string, bool), prefix string, src Source, sources Sources) (bool, error) {
+	if v.Kind() == reflect.Pointer {
+		if !v.IsNil() {
+			return applyEnvValue(v.Elem(), envPrefix, lookup, prefix, src, sources)
+		}
+
+		elem := reflect.New(v.Type().Elem())
+		applied, err := applyEnvValue(elem.Elem(), envPrefix, lookup, prefix, src, sources)
+		if applied && v.CanSet() {
+			v.Set(elem)
+		}
+		return applied, err
+	}
+	if v.Kind() != reflect.Struct {
+		return false, nil
+	}
+
+	applied := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if skipField(field) || field.Tag.Get("env") == "-" {
+			continue
+		}
+
+		path := prefix
+		if !isInline(field) {
+			path = joinPath(prefix, fieldName(field))
+		}
+
+		if isNested(field.Type) {
+			ok, err := applyEnvValue(v.Field(i), envPrefix, lookup, path, src, sources)
+			if err != nil {
+				return applied, err
+			}
+			applied = applied || ok
+			continue
+		}
+
+		name := envName(field, envPrefix, path)
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(v.Field(i), raw); err != nil {
+			return applied, fmt.Errorf("failed to apply environment variable %s to %s: %w", name, path, err)
+		}
+		sources[path] = src
+		applied = true
+	}
+
+	return applied, nil
+}
+
+// setFromString parses raw into v according to v's type. Slices are read as
+// comma-separated lists and time.Duration accepts values such as "1m30s".
+func setFromString(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setFromString(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := utils.StringToArrayString(raw, ",", true)
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFromString(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type: %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/anthanhphan/saturday/kafka"
+	"github.com/anthanhphan/saturday/mail"
+)
+
+// App aggregates the configuration of every saturday component so a service
+// can be bootstrapped from a single file. Optional components are pointers and
+// stay nil (and unvalidated) when their section is absent.
+//
+// Example:
+//
+//	path, err := config.LookupConfigPath(env, "yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cfg, err := config.NewConfig(path, &models.App{}, config.WithEnv("APP"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	_, undo := logger.InitLogger(cfg.Logger.ToConfig())
+//	defer undo()
+//	db, err := cfg.Postgres.NewDatabase()
+type App struct {
+	Service  Service          `yaml:"service" json:"service"`
+	Logger   Logger           `yaml:"logger" json:"logger"`
+	Postgres *Postgres        `yaml:"postgres" json:"postgres"`
+	Jwt      *Jwt             `yaml:"jwt" json:"jwt"`
+	Kafka    *kafka.Config    `yaml:"kafka" json:"kafka"`
+	Mail     *mail.MailConfig `yaml:"mail" json:"mail"`
+}
@@ -1,8 +1,39 @@
 package models
 
+import (
+	"fmt"
+	"os"
+
+	"github.com/anthanhphan/saturday/jwt"
+)
+
 type Jwt struct {
 	PrivateKeyPath     string `yaml:"private_key_path" json:"private_key_path"`
 	PublicKeyPath      string `yaml:"public_key_path" json:"public_key_path"`
 	AccessTokenExpiry  int64  `yaml:"access_token_expiry" json:"access_token_expiry"`
 	RefreshTokenExpiry int64  `yaml:"refresh_token_expiry" json:"refresh_token_expiry"`
 }
+
+// NewJwt reads the PEM-encoded key files and creates a jwt.Jwt from them.
+//
+// Returns:
+//   - jwt.Jwt: The JWT service
+//   - error: Error if a key file could not be read
+//
+// Example:
+//
+//	j, err := cfg.Jwt.NewJwt()
+//	token, err := j.Generate(&jwt.Payload{UserId: 1}, cfg.Jwt.AccessTokenExpiry)
+func (j Jwt) NewJwt() (jwt.Jwt, error) {
+	privateKey, err := os.ReadFile(j.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	publicKey, err := os.ReadFile(j.PublicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	return jwt.NewJwt(string(privateKey), string(publicKey)), nil
+}
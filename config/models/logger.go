@@ -1,5 +1,7 @@
 package models
 
+import "github.com/anthanhphan/saturday/logger"
+
 type Logger struct {
 	DisableCaller     bool   `yaml:"disable_caller" json:"disable_caller"`
 	DisableStacktrace bool   `yaml:"disable_stacktrace" json:"disable_stacktrace"`
@@ -7,3 +9,22 @@ type Logger struct {
 	Level             string `yaml:"level" json:"level"`
 	Encoding          string `yaml:"encoding" json:"encoding"`
 }
+
+// ToConfig converts the Logger configuration into a logger.Config.
+//
+// Returns:
+//   - *logger.Config: The configuration accepted by logger.InitLogger
+//
+// Example:
+//
+//	log, undo := logger.InitLogger(cfg.Logger.ToConfig())
+//	defer undo()
+func (l Logger) ToConfig() *logger.Config {
+	return &logger.Config{
+		DisableCaller:     l.DisableCaller,
+		DisableStacktrace: l.DisableStacktrace,
+		EnableDevMode:     l.EnableDevMode,
+		Level:             logger.LevelType(l.Level),
+		Encoding:          logger.EncodingType(l.Encoding),
+	}
+}
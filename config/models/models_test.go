@@ -0,0 +1,133 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/config"
+	"github.com/anthanhphan/saturday/db/postgres"
+	"github.com/anthanhphan/saturday/http/server"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/anthanhphan/saturday/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresToConnection(t *testing.T) {
+	p := Postgres{
+		Host:                  "localhost",
+		Port:                  5432,
+		Database:              "app",
+		User:                  "postgres",
+		Password:              "secret",
+		SSLCertPath:           "/certs/client.crt",
+		SSLKeyPath:            "/certs/client.key",
+		SSLRootCertPath:       "/certs/ca.crt",
+		SSLMode:               "verify-full",
+		MaxOpenConnections:    10,
+		MaxIdleConnections:    5,
+		ConnectionMaxIdleTime: 1500,
+		ConnectionMaxLifeTime: 60000,
+		ConnectionTimeout:     250,
+	}
+
+	assert.Equal(t, postgres.Connection{
+		Host:                  "localhost",
+		Port:                  5432,
+		Database:              "app",
+		User:                  "postgres",
+		Password:              "secret",
+		SSLCert:               "/certs/client.crt",
+		SSLKey:                "/certs/client.key",
+		SSLRootCert:           "/certs/ca.crt",
+		SSLMode:               postgres.VerifyFull,
+		MaxOpenConnections:    10,
+		MaxIdleConnections:    5,
+		ConnectionMaxIdleTime: 1500 * time.Millisecond,
+		ConnectionMaxLifeTime: time.Minute,
+		ConnectionTimeout:     250 * time.Millisecond,
+	}, p.ToConnection())
+}
+
+func TestLoggerToConfig(t *testing.T) {
+	l := Logger{DisableCaller: true, Level: "warn", Encoding: "console"}
+
+	assert.Equal(t, &logger.Config{
+		DisableCaller: true,
+		Level:         logger.LevelWarn,
+		Encoding:      logger.EncodingConsole,
+	}, l.ToConfig())
+}
+
+func TestJwtNewJwt(t *testing.T) {
+	keys, err := utils.GenerateRsaKeyPair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privatePath, []byte(keys.PrivateKey), 0o600))
+	require.NoError(t, os.WriteFile(publicPath, []byte(keys.PublicKey), 0o600))
+
+	j, err := Jwt{PrivateKeyPath: privatePath, PublicKeyPath: publicPath}.NewJwt()
+	require.NoError(t, err)
+	assert.NotNil(t, j)
+
+	_, err = Jwt{PrivateKeyPath: filepath.Join(dir, "missing.pem")}.NewJwt()
+	assert.Error(t, err)
+}
+
+func TestServiceServerOptions(t *testing.T) {
+	srv := server.NewHttpServer(Service{Name: "api", Port: 8080}.ServerOptions()...)
+
+	assert.Equal(t, "api", srv.Name)
+	assert.Equal(t, int64(8080), srv.Port)
+}
+
+func TestLoadApp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+service:
+  name: api
+  port: 8080
+logger:
+  level: info
+postgres:
+  host: localhost
+  password: env:DB_PASSWORD
+kafka:
+  addrs: [localhost:9092]
+  acl:
+    enable: true
+    user: app
+`), 0o600))
+
+	cfg, err := config.NewConfig(path, &App{},
+		config.WithEnv("APP"),
+		config.WithLookupEnv(func(key string) (string, bool) {
+			values := map[string]string{"DB_PASSWORD": "secret", "APP_KAFKA_ACL_PASSWORD": "kafka-secret"}
+			value, ok := values[key]
+			return value, ok
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "api", cfg.Service.Name)
+	require.NotNil(t, cfg.Postgres)
+	assert.Equal(t, "secret", cfg.Postgres.ToConnection().Password)
+	require.NotNil(t, cfg.Kafka)
+	assert.Equal(t, "kafka-secret", cfg.Kafka.Acl.Password.Value())
+	assert.Nil(t, cfg.Jwt)
+	assert.Nil(t, cfg.Mail)
+}
+
+func TestLoadAppValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("service:\n  name: api\nkafka:\n  topics: [orders]\n"), 0o600))
+
+	_, err := config.NewConfig(path, &App{})
+	assert.ErrorContains(t, err, "service.port: required")
+	assert.ErrorContains(t, err, "kafka.addrs: min=1")
+}
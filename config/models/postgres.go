@@ -1,21 +1,69 @@
 package models
 
+import (
+	"time"
+
+	"github.com/anthanhphan/saturday/db/postgres"
+	"github.com/anthanhphan/saturday/secret"
+)
+
 type Postgres struct {
-	Host                  string `yaml:"host"`
-	Port                  int64  `yaml:"port"`
-	Database              string `yaml:"database"`
-	User                  string `yaml:"user"`
-	Password              string `yaml:"password"`
-	TimeZone              string `yaml:"time_zone"`
-	SSLCertPath           string `yaml:"ssl_cert_path"`
-	SSLKeyPath            string `yaml:"ssl_key_path"`
-	SSLRootCertPath       string `yaml:"ssl_root_cert_path"`
-	SSLMode               string `yaml:"ssl_mode"`
-	LogLevel              string `yaml:"log_level"`
-	SlowQueryThreshold    int64  `yaml:"slow_query_threshold"`
-	MaxOpenConnections    int    `yaml:"max_open_connections"`
-	MaxIdleConnections    int    `yaml:"max_idle_connections"`
-	ConnectionMaxIdleTime int64  `yaml:"connection_max_idle_time"`
-	ConnectionMaxLifeTime int64  `yaml:"connection_max_life_time"`
-	ConnectionTimeout     int64  `yaml:"connection_timeout"`
+	Host                  string        `yaml:"host" json:"host"`
+	Port                  int64         `yaml:"port" json:"port"`
+	Database              string        `yaml:"database" json:"database"`
+	User                  string        `yaml:"user" json:"user"`
+	Password              secret.Secret `yaml:"password" json:"password"`
+	TimeZone              string        `yaml:"time_zone" json:"time_zone"`
+	SSLCertPath           string        `yaml:"ssl_cert_path" json:"ssl_cert_path"`
+	SSLKeyPath            string        `yaml:"ssl_key_path" json:"ssl_key_path"`
+	SSLRootCertPath       string        `yaml:"ssl_root_cert_path" json:"ssl_root_cert_path"`
+	SSLMode               string        `yaml:"ssl_mode" json:"ssl_mode"`
+	LogLevel              string        `yaml:"log_level" json:"log_level"`
+	SlowQueryThreshold    int64         `yaml:"slow_query_threshold" json:"slow_query_threshold"`
+	MaxOpenConnections    int           `yaml:"max_open_connections" json:"max_open_connections"`
+	MaxIdleConnections    int           `yaml:"max_idle_connections" json:"max_idle_connections"`
+	ConnectionMaxIdleTime int64         `yaml:"connection_max_idle_time" json:"connection_max_idle_time"`
+	ConnectionMaxLifeTime int64         `yaml:"connection_max_life_time" json:"connection_max_life_time"`
+	ConnectionTimeout     int64         `yaml:"connection_timeout" json:"connection_timeout"`
+}
+
+// ToConnection converts the Postgres configuration into a postgres.Connection.
+// Durations are configured in milliseconds and the certificate path fields map
+// onto the SSL certificate fields of the connection.
+//
+// Returns:
+//   - postgres.Connection: The connection settings
+//
+// Example:
+//
+//	conn := cfg.Postgres.ToConnection()
+//	db, err := postgres.NewDatabase(conn, cfg.Postgres.LogLevel, cfg.Postgres.SlowQueryThreshold)
+func (p Postgres) ToConnection() postgres.Connection {
+	return postgres.Connection{
+		Host:                  p.Host,
+		Port:                  p.Port,
+		Database:              p.Database,
+		User:                  p.User,
+		Password:              p.Password.Value(),
+		TimeZone:              p.TimeZone,
+		SSLCert:               p.SSLCertPath,
+		SSLKey:                p.SSLKeyPath,
+		SSLRootCert:           p.SSLRootCertPath,
+		SSLMode:               postgres.SSLMode(p.SSLMode),
+		MaxOpenConnections:    p.MaxOpenConnections,
+		MaxIdleConnections:    p.MaxIdleConnections,
+		ConnectionMaxIdleTime: time.Duration(p.ConnectionMaxIdleTime) * time.Millisecond,
+		ConnectionMaxLifeTime: time.Duration(p.ConnectionMaxLifeTime) * time.Millisecond,
+		ConnectionTimeout:     time.Duration(p.ConnectionTimeout) * time.Millisecond,
+	}
+}
+
+// NewDatabase opens a postgres.Database using the connection, log level and
+// slow query threshold from the configuration.
+//
+// Returns:
+//   - *postgres.Database: The opened database
+//   - error: Any error encountered while connecting
+func (p Postgres) NewDatabase() (*postgres.Database, error) {
+	return postgres.NewDatabase(p.ToConnection(), p.LogLevel, p.SlowQueryThreshold)
 }
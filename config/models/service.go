@@ -1,8 +1,25 @@
 package models
 
+import "github.com/anthanhphan/saturday/http/server"
+
 type Service struct {
 	Host    string `yaml:"host" json:"host"`
-	Port    int64  `yaml:"port" json:"port"`
+	Port    int64  `yaml:"port" json:"port" validate:"required"`
 	Name    string `yaml:"name" json:"name"`
 	Timeout int64  `yaml:"timeout" json:"timeout"`
 }
+
+// ServerOptions converts the Service configuration into options for server.NewHttpServer.
+//
+// Returns:
+//   - []server.Option: Options setting the server name and port
+//
+// Example:
+//
+//	srv := server.NewHttpServer(cfg.Service.ServerOptions()...)
+func (s Service) ServerOptions() []server.Option {
+	return []server.Option{
+		server.AddName(s.Name),
+		server.AddPort(s.Port),
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import "os"
+
+// Option is a function type that modifies how NewConfig loads a configuration.
+type Option func(*options)
+
+type options struct {
+	baseFile  string
+	envEnable bool
+	envPrefix string
+	lookupEnv func(key string) (string, bool)
+	sources   *Sources
+	resolvers map[string]Resolver
+}
+
+// newOptions applies the provided options on top of the defaults.
+func newOptions(opts []Option) *options {
+	o := &options{
+		lookupEnv: os.LookupEnv,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithBaseFile returns an Option that loads a shared base file before the
+// environment-specific file, so the latter only has to contain overrides.
+//
+// Parameters:
+//   - path: Path to the base configuration file
+//
+// Returns:
+//   - Option: Function that sets the base file
+//
+// Example:
+//
+//	path, err := LookupConfigPath("staging")
+//	...
+//	cfg, err := NewConfig(path, &MyConfig{}, WithBaseFile("./env/env.base.json"))
+func WithBaseFile(path string) Option {
+	return func(o *options) {
+		o.baseFile = path
+	}
+}
+
+// WithEnv returns an Option that enables environment variable overrides.
+// A field is overridden by the variable named in its `env:"..."` tag, or by a
+// name derived from its path: the prefix followed by the upper-cased path, with
+// dots replaced by underscores (e.g. "acl.password" -> "APP_ACL_PASSWORD").
+//
+// Parameters:
+//   - prefix: Prefix for derived variable names (may be empty)
+//
+// Returns:
+//   - Option: Function that enables the environment layer
+//
+// Example:
+//
+//	// APP_ACL_PASSWORD=secret overrides Acl.Password in kafka.Config
+//	cfg, err := NewConfig("config.yaml", &kafka.Config{}, WithEnv("APP"))
+func WithEnv(prefix string) Option {
+	return func(o *options) {
+		o.envEnable = true
+		o.envPrefix = prefix
+	}
+}
+
+// WithLookupEnv returns an Option that replaces os.LookupEnv as the source of
+// environment variables. It is mostly useful in tests.
+//
+// Parameters:
+//   - lookup: Function that returns the value of a variable and whether it is set
+//
+// Returns:
+//   - Option: Function that sets the lookup function
+func WithLookupEnv(lookup func(key string) (string, bool)) Option {
+	return func(o *options) {
+		if lookup != nil {
+			o.lookupEnv = lookup
+		}
+	}
+}
+
+// WithSources returns an Option that reports which source supplied the final
+// value of every field once loading succeeds.
+//
+// Parameters:
+//   - dst: Pointer that receives the Sources report
+//
+// Returns:
+//   - Option: Function that sets the report destination
+//
+// Example:
+//
+//	var sources Sources
+//	cfg, err := NewConfig("config.yaml", &MyConfig{}, WithEnv("APP"), WithSources(&sources))
+//	fmt.Println(sources["host"]) // "env", "file", "base" or "default"
+func WithSources(dst *Sources) Option {
+	return func(o *options) {
+		o.sources = dst
+	}
+}
+
+// WithResolver returns an Option that resolves a placeholder scheme for this
+// load only, taking precedence over resolvers registered with RegisterResolver.
+//
+// Parameters:
+//   - scheme: The placeholder prefix before the first colon
+//   - resolver: The resolver handling that scheme
+//
+// Returns:
+//   - Option: Function that adds the resolver
+//
+// Example:
+//
+//	cfg, err := NewConfig("config.yaml", &models.Postgres{}, WithResolver("rsa", NewRsaResolver(privateKey)))
+func WithResolver(scheme string, resolver Resolver) Option {
+	return func(o *options) {
+		if o.resolvers == nil {
+			o.resolvers = map[string]Resolver{}
+		}
+		o.resolvers[scheme] = resolver
+	}
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/anthanhphan/saturday/secret"
+	"github.com/anthanhphan/saturday/utils"
+)
+
+// Secret is a string that hides its value when printed or marshalled, see
+// secret.Secret. Placeholders in Secret fields are resolved while loading.
+type Secret = secret.Secret
+
+// Resolver turns the reference part of a secret placeholder into the secret value.
+// For "file:///run/secrets/db_pw" the resolver registered for "file" receives
+// "///run/secrets/db_pw".
+type Resolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc adapts an ordinary function to the Resolver interface.
+type ResolverFunc func(ref string) (string, error)
+
+// Resolve calls f(ref).
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]Resolver{}
+)
+
+// RegisterResolver registers a Resolver for a placeholder scheme for every
+// subsequent NewConfig call. The built-in "file" and "env" schemes can be
+// replaced the same way.
+//
+// Parameters:
+//   - scheme: The placeholder prefix before the first colon (e.g. "vault")
+//   - resolver: The resolver handling that scheme
+//
+// Example:
+//
+//	config.RegisterResolver("aes", config.NewAesGcmResolver(key))
+//	// password: "aes:3q2+7w..." is now decrypted while loading
+func RegisterResolver(scheme string, resolver Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	resolvers[scheme] = resolver
+}
+
+// NewAesGcmResolver returns a Resolver for base64-encoded values produced by utils.AesGcmEncrypt.
+//
+// Parameters:
+//   - key: The AES key (16, 24 or 32 bytes)
+//
+// Returns:
+//   - Resolver: Resolver decrypting the placeholder reference
+func NewAesGcmResolver(key []byte) Resolver {
+	return ResolverFunc(func(ref string) (string, error) {
+		ciphertext, err := base64.StdEncoding.DecodeString(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+		}
+
+		plaintext, err := utils.AesGcmDecrypt(key, ciphertext)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt: %w", err)
+		}
+		return string(plaintext), nil
+	})
+}
+
+// NewRsaResolver returns a Resolver for base64-encoded values produced by utils.RsaEncrypt.
+//
+// Parameters:
+//   - privateKey: PEM-encoded RSA private key, as generated by utils.GenerateRsaKeyPair
+//
+// Returns:
+//   - Resolver: Resolver decrypting the placeholder reference
+func NewRsaResolver(privateKey string) Resolver {
+	return ResolverFunc(func(ref string) (string, error) {
+		ciphertext, err := base64.StdEncoding.DecodeString(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+		}
+
+		plaintext, err := utils.RsaDecrypt(privateKey, ciphertext)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt: %w", err)
+		}
+		return string(plaintext), nil
+	})
+}
+
+// resolveFile reads a secret from a file, e.g. "file:///run/secrets/db_pw".
+// Trailing newlines, which most secret stores append, are removed.
+func resolveFile(ref string) (string, error) {
+	path := strings.TrimPrefix(ref, "//")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolversFor returns the resolvers used for a single load: the built-in
+// schemes, then registered resolvers, then those passed via WithResolver.
+func resolversFor(o *options) map[string]Resolver {
+	result := map[string]Resolver{
+		"file": ResolverFunc(resolveFile),
+		"env": ResolverFunc(func(ref string) (string, error) {
+			value, ok := o.lookupEnv(ref)
+			if !ok {
+				return "", fmt.Errorf("environment variable %s is not set", ref)
+			}
+			return value, nil
+		}),
+	}
+
+	resolversMu.RLock()
+	for scheme, resolver := range resolvers {
+		result[scheme] = resolver
+	}
+	resolversMu.RUnlock()
+
+	for scheme, resolver := range o.resolvers {
+		result[scheme] = resolver
+	}
+
+	return result
+}
+
+// splitPlaceholder splits "scheme:ref" and reports whether scheme looks like a scheme name.
+func splitPlaceholder(value string) (string, string, bool) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok || scheme == "" {
+		return "", "", false
+	}
+	for _, r := range scheme {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.') {
+			return "", "", false
+		}
+	}
+	return scheme, ref, true
+}
+
+// resolveString replaces a placeholder with its secret. Values whose scheme has
+// no registered resolver are returned unchanged.
+func resolveString(value string, resolvers map[string]Resolver) (string, bool, error) {
+	scheme, ref, ok := splitPlaceholder(value)
+	if !ok {
+		return value, false, nil
+	}
+
+	resolver, ok := resolvers[scheme]
+	if !ok {
+		return value, false, nil
+	}
+
+	secret, err := resolver.Resolve(ref)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve %s secret: %w", scheme, err)
+	}
+	return secret, true, nil
+}
+
+var secretType = reflect.TypeOf(Secret(""))
+
+// resolveSecrets walks v and resolves placeholders in Secret fields and Secret
+// slices. Other string fields are left as written.
+func resolveSecrets(v reflect.Value, resolvers map[string]Resolver, prefix string) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if skipField(field) {
+			continue
+		}
+
+		path := prefix
+		if !isInline(field) {
+			path = joinPath(prefix, fieldName(field))
+		}
+
+		fv := v.Field(i)
+		if isNested(field.Type) {
+			if err := resolveSecrets(fv, resolvers, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch {
+		case fv.Type() == secretType:
+			if err := resolveValue(fv, resolvers, path); err != nil {
+				return err
+			}
+		case fv.Kind() == reflect.Slice && fv.Type().Elem() == secretType:
+			for j := 0; j < fv.Len(); j++ {
+				if err := resolveValue(fv.Index(j), resolvers, fmt.Sprintf("%s[%d]", path, j)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveValue(v reflect.Value, resolvers map[string]Resolver, path string) error {
+	secret, ok, err := resolveString(v.String(), resolvers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if ok {
+		v.SetString(secret)
+	}
+	return nil
+}
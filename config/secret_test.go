@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/anthanhphan/saturday/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secretConfig struct {
+	Host      string   `yaml:"host"`
+	Password  Secret   `yaml:"password"`
+	ApiKey    Secret   `yaml:"api_key"`
+	Token     Secret   `yaml:"token"`
+	Encrypted Secret   `yaml:"encrypted"`
+	Keys      []Secret `yaml:"keys"`
+	Addrs     []string `yaml:"addrs"`
+}
+
+func TestNewConfigSecrets(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	ciphertext, err := utils.AesGcmEncrypt(key, []byte("aes-secret"))
+	require.NoError(t, err)
+
+	rsaKey, err := utils.GenerateRsaKeyPair()
+	require.NoError(t, err)
+	rsaCiphertext, err := utils.RsaEncrypt(rsaKey.PublicKey, []byte("rsa-secret"))
+	require.NoError(t, err)
+
+	secretFile := writeFile(t, "db_pw", "file-secret\n")
+	path := writeFile(t, "config.yaml", fmt.Sprintf(
+		"host: env:HOST\npassword: file://%s\napi_key: env:API_KEY\ntoken: rsa:%s\nencrypted: aes:%s\n"+
+			"keys: [env:API_KEY, plain]\naddrs: [env:BROKER, broker-2:9092]\n",
+		secretFile,
+		base64.StdEncoding.EncodeToString(rsaCiphertext),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	))
+
+	RegisterResolver("rsa", NewRsaResolver(rsaKey.PrivateKey))
+	defer func() {
+		resolversMu.Lock()
+		delete(resolvers, "rsa")
+		resolversMu.Unlock()
+	}()
+
+	cfg, err := NewConfig(path, &secretConfig{},
+		WithResolver("aes", NewAesGcmResolver(key)),
+		WithLookupEnv(mapLookup(map[string]string{"HOST": "db:5432", "API_KEY": "env-secret", "BROKER": "broker-1:9092"})),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "env:HOST", cfg.Host)
+	assert.Equal(t, "file-secret", cfg.Password.Value())
+	assert.Equal(t, "env-secret", cfg.ApiKey.Value())
+	assert.Equal(t, "rsa-secret", cfg.Token.Value())
+	assert.Equal(t, "aes-secret", cfg.Encrypted.Value())
+	assert.Equal(t, []Secret{"env-secret", "plain"}, cfg.Keys)
+	assert.Equal(t, []string{"env:BROKER", "broker-2:9092"}, cfg.Addrs)
+}
+
+func TestNewConfigSecretErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing file", "password: file:///does/not/exist\n"},
+		{"missing env", "api_key: env:MISSING\n"},
+		{"bad ciphertext", "encrypted: aes:not-base64!\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, "config.yaml", tt.content)
+
+			_, err := NewConfig(path, &secretConfig{},
+				WithResolver("aes", NewAesGcmResolver(bytes.Repeat([]byte{7}, 32))),
+				WithLookupEnv(mapLookup(nil)),
+			)
+			assert.Error(t, err)
+		})
+	}
+}
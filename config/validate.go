@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/anthanhphan/saturday/validate"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	validatorOnce sync.Once
+	validatorInst *validator.Validate
+)
+
+// FieldError describes a single configuration field that failed validation.
+//
+// Fields:
+//   - Path: Full dotted path of the field as written in the file (e.g. "acl.user")
+//   - Tag: The validation rule that failed (e.g. "required", "min")
+//   - Param: The rule parameter, if any (e.g. "1" for "min=1")
+type FieldError struct {
+	Path  string
+	Tag   string
+	Param string
+}
+
+// String returns the field error as "path: tag=param".
+func (e FieldError) String() string {
+	if e.Param == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Tag)
+	}
+	return fmt.Sprintf("%s: %s=%s", e.Path, e.Tag, e.Param)
+}
+
+// ValidationError aggregates every field of a configuration that failed validation.
+// Field values are deliberately left out so secrets never leak into logs.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface and lists every invalid field.
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		parts = append(parts, field.String())
+	}
+	return "invalid config: " + strings.Join(parts, "; ")
+}
+
+// Validate checks a configuration struct against its `validate:"..."` tags
+// using go-playground/validator and reports every failing field by its full
+// YAML/JSON path. Non-struct values are not validated.
+//
+// Parameters:
+//   - cfg: The configuration struct or pointer to it
+//
+// Returns:
+//   - error: A *ValidationError listing every invalid field, or nil
+//
+// Example:
+//
+//	type Service struct {
+//	    Port  int64    `yaml:"port" validate:"required"`
+//	    Addrs []string `yaml:"addrs" validate:"min=1"`
+//	}
+//
+//	err := Validate(&Service{})
+//	// err.Error() == "invalid config: port: required; addrs: min=1"
+func Validate(cfg any) error {
+	t := reflect.TypeOf(cfg)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	err := getValidator().Struct(cfg)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	result := &ValidationError{Fields: make([]FieldError, 0, len(fieldErrs))}
+	for _, fieldErr := range fieldErrs {
+		// Drop the root struct name from the namespace.
+		_, path, _ := strings.Cut(fieldErr.Namespace(), ".")
+		result.Fields = append(result.Fields, FieldError{
+			Path:  path,
+			Tag:   fieldErr.Tag(),
+			Param: fieldErr.Param(),
+		})
+	}
+
+	return result
+}
+
+// getValidator returns a shared validator that names fields after their yaml/json tags.
+func getValidator() *validator.Validate {
+	validatorOnce.Do(func() {
+		validatorInst = validator.New()
+		validatorInst.RegisterTagNameFunc(fieldName)
+	})
+	return validatorInst
+}
+
+// applyDefaults fills zero-valued leaf fields from their `default:"..."` tags.
+// Slices use comma-separated values, like environment variables.
+func applyDefaults(v reflect.Value, prefix string) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if skipField(field) {
+			continue
+		}
+
+		path := prefix
+		if !isInline(field) {
+			path = joinPath(prefix, fieldName(field))
+		}
+
+		if isNested(field.Type) {
+			if err := applyDefaults(v.Field(i), path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !validate.IsZero(v.Field(i).Interface()) {
+			continue
+		}
+		if err := setFromString(v.Field(i), def); err != nil {
+			return fmt.Errorf("failed to apply default value to %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
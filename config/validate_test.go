@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedConfig struct {
+	Name    string        `yaml:"name" json:"name" validate:"required"`
+	Port    int64         `yaml:"port" json:"port" default:"8080" validate:"min=1"`
+	Addrs   []string      `yaml:"addrs" json:"addrs" default:"localhost:9092" validate:"min=1,dive,required"`
+	Level   string        `yaml:"level" json:"level" default:"info" validate:"oneof=debug info warn error"`
+	Timeout time.Duration `yaml:"timeout" json:"timeout" default:"5s"`
+	Acl     struct {
+		Enable bool   `yaml:"enable" json:"enable"`
+		User   string `yaml:"user" json:"user" validate:"required_if=Enable true"`
+	} `yaml:"acl" json:"acl"`
+}
+
+func TestNewConfigDefaults(t *testing.T) {
+	path := writeFile(t, "config.yaml", "name: api\nlevel: warn\n")
+
+	cfg, err := NewConfig(path, &validatedConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8080), cfg.Port)
+	assert.Equal(t, []string{"localhost:9092"}, cfg.Addrs)
+	assert.Equal(t, "warn", cfg.Level)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+}
+
+func TestNewConfigValidation(t *testing.T) {
+	path := writeFile(t, "config.yaml", "port: 0\naddrs: []\nlevel: trace\nacl:\n  enable: true\n")
+
+	_, err := NewConfig(path, &validatedConfig{})
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{
+		{Path: "name", Tag: "required"},
+		{Path: "port", Tag: "min", Param: "1"},
+		{Path: "addrs", Tag: "min", Param: "1"},
+		{Path: "level", Tag: "oneof", Param: "debug info warn error"},
+		{Path: "acl.user", Tag: "required_if", Param: "Enable true"},
+	}, validationErr.Fields)
+	assert.Contains(t, err.Error(), "acl.user: required_if")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		wantErr bool
+	}{
+		{"valid struct", &validatedConfig{Name: "api", Port: 1, Addrs: []string{"a"}, Level: "info"}, false},
+		{"invalid struct", validatedConfig{}, true},
+		{"non struct", map[string]any{}, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultWatchInterval = time.Second
+
+// WatchOption is a function type that modifies Watcher configuration.
+type WatchOption[T any] func(*Watcher[T])
+
+// Watcher keeps an atomically swapped snapshot of a configuration file and
+// reloads it whenever the file (or its base file) changes on disk.
+//
+// Snapshots returned by Get and passed to subscribers are shared and must be
+// treated as read-only.
+type Watcher[T any] struct {
+	path        string
+	interval    time.Duration
+	loadOptions []Option
+	newModel    func() *T
+	validate    func(*T) error
+	onError     func(error)
+
+	current  atomic.Pointer[T]
+	reloadMu sync.Mutex
+
+	mu          sync.Mutex
+	nextId      int
+	subscribers map[int]func(old, new *T)
+	files       map[string]fileState
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch loads the configuration at configPath and starts watching it for changes.
+// The initial load must succeed; later reloads that fail keep the previous
+// snapshot and report the error through the error handler.
+//
+// Parameters:
+//   - ctx: Context that stops the watcher when cancelled
+//   - configPath: Path to the configuration file
+//   - opts: Optional watcher options
+//
+// Returns:
+//   - *Watcher[T]: The running watcher holding the initial snapshot
+//   - error: Error if the initial load failed
+//
+// Example:
+//
+//	w, err := config.Watch[models.Logger](ctx, "./env/env.local.yml",
+//	    config.WithLoadOptions[models.Logger](config.WithEnv("APP")),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer w.Close()
+//
+//	w.Subscribe(func(old, new *models.Logger) {
+//	    if old.Level != new.Level {
+//	        atomicLevel.SetLevel(parseLevel(new.Level))
+//	    }
+//	})
+func Watch[T any](ctx context.Context, configPath string, opts ...WatchOption[T]) (*Watcher[T], error) {
+	log := zap.L().With(zap.String("prefix", "Watch")).Sugar()
+
+	w := &Watcher[T]{
+		path:        configPath,
+		interval:    defaultWatchInterval,
+		newModel:    func() *T { return new(T) },
+		onError:     func(err error) { log.Errorf("failed to reload config %s: %v", configPath, err) },
+		subscribers: map[int]func(old, new *T){},
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	files := w.statFiles()
+	snapshot, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(snapshot)
+	w.files = files
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	go w.run(ctx)
+
+	return w, nil
+}
+
+// WithInterval returns a WatchOption that sets how often the files are checked for changes.
+//
+// Parameters:
+//   - interval: Polling interval (defaults to one second)
+//
+// Returns:
+//   - WatchOption[T]: Function that sets the polling interval
+func WithInterval[T any](interval time.Duration) WatchOption[T] {
+	return func(w *Watcher[T]) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithLoadOptions returns a WatchOption that passes options to NewConfig on every load.
+// A base file set through WithBaseFile is watched as well.
+//
+// Parameters:
+//   - opts: Options forwarded to NewConfig
+//
+// Returns:
+//   - WatchOption[T]: Function that sets the load options
+func WithLoadOptions[T any](opts ...Option) WatchOption[T] {
+	return func(w *Watcher[T]) {
+		w.loadOptions = append(w.loadOptions, opts...)
+	}
+}
+
+// WithModel returns a WatchOption that creates the model each load starts from,
+// which lets callers provide default values.
+//
+// Parameters:
+//   - newModel: Function returning a fresh model
+//
+// Returns:
+//   - WatchOption[T]: Function that sets the model factory
+func WithModel[T any](newModel func() *T) WatchOption[T] {
+	return func(w *Watcher[T]) {
+		if newModel != nil {
+			w.newModel = newModel
+		}
+	}
+}
+
+// WithValidator returns a WatchOption that rejects snapshots failing the given check.
+//
+// Parameters:
+//   - validate: Function returning an error for an invalid configuration
+//
+// Returns:
+//   - WatchOption[T]: Function that sets the validator
+func WithValidator[T any](validate func(*T) error) WatchOption[T] {
+	return func(w *Watcher[T]) {
+		w.validate = validate
+	}
+}
+
+// WithErrorHandler returns a WatchOption that receives reload errors instead of the default logger.
+//
+// Parameters:
+//   - onError: Function called with every failed reload
+//
+// Returns:
+//   - WatchOption[T]: Function that sets the error handler
+func WithErrorHandler[T any](onError func(error)) WatchOption[T] {
+	return func(w *Watcher[T]) {
+		if onError != nil {
+			w.onError = onError
+		}
+	}
+}
+
+// Get returns the current configuration snapshot.
+//
+// Returns:
+//   - *T: The latest successfully loaded configuration
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers a callback invoked with the previous and the new snapshot
+// after every successful reload. Callbacks run on the watcher goroutine in
+// registration order.
+//
+// Parameters:
+//   - fn: Callback receiving the old and new snapshots
+//
+// Returns:
+//   - func(): Function that removes the subscription
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextId
+	w.nextId++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Reload loads the configuration immediately and swaps the snapshot on success.
+// On failure the previous snapshot is kept and the error is returned.
+//
+// Returns:
+//   - error: Error if the configuration could not be loaded or validated
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	w.files = w.statFiles()
+	w.mu.Unlock()
+
+	return w.reload()
+}
+
+// Close stops watching and waits for the watcher goroutine to exit.
+func (w *Watcher[T]) Close() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *Watcher[T]) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+			if err := w.reload(); err != nil {
+				w.onError(err)
+			}
+		}
+	}
+}
+
+func (w *Watcher[T]) reload() error {
+	// Serialize reloads so subscribers observe snapshots in order.
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	snapshot, err := w.load()
+	if err != nil {
+		return err
+	}
+
+	old := w.current.Swap(snapshot)
+
+	w.mu.Lock()
+	subscribers := make([]func(old, new *T), 0, len(w.subscribers))
+	for id := 0; id < w.nextId; id++ {
+		if fn, ok := w.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, snapshot)
+	}
+
+	return nil
+}
+
+func (w *Watcher[T]) load() (*T, error) {
+	snapshot, err := NewConfig(w.path, w.newModel(), w.loadOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.validate != nil {
+		if err := w.validate(snapshot); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// changed reports whether any watched file differs from the last check.
+func (w *Watcher[T]) changed() bool {
+	files := w.statFiles()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := len(files) != len(w.files)
+	for path, state := range files {
+		if prev, ok := w.files[path]; !ok || prev != state {
+			changed = true
+		}
+	}
+	w.files = files
+
+	return changed
+}
+
+// statFiles returns the modification time and size of every watched file.
+// Files that cannot be read are omitted, so they count as changed once they reappear.
+func (w *Watcher[T]) statFiles() map[string]fileState {
+	paths := []string{w.path}
+	if base := newOptions(w.loadOptions).baseFile; base != "" {
+		paths = append(paths, base)
+	}
+
+	files := make(map[string]fileState, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return files
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "config.yaml", "host: first\nport: 1\n")
+
+	errCh := make(chan error, 1)
+	w, err := Watch(context.Background(), path,
+		WithInterval[testConfig](10*time.Millisecond),
+		WithErrorHandler[testConfig](func(err error) { errCh <- err }),
+	)
+	require.NoError(t, err)
+	defer w.Close()
+	assert.Equal(t, "first", w.Get().Host)
+
+	changes := make(chan [2]*testConfig, 1)
+	w.Subscribe(func(old, new *testConfig) { changes <- [2]*testConfig{old, new} })
+
+	t.Run("reload on change", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("host: second\nport: 2\n"), 0o600))
+
+		select {
+		case change := <-changes:
+			assert.Equal(t, "first", change[0].Host)
+			assert.Equal(t, "second", change[1].Host)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for reload")
+		}
+		assert.Equal(t, "second", w.Get().Host)
+	})
+
+	t.Run("keep snapshot on parse error", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("host: [unterminated\n"), 0o600))
+
+		select {
+		case err := <-errCh:
+			assert.Error(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for reload error")
+		}
+		assert.Equal(t, "second", w.Get().Host)
+	})
+}
+
+func TestWatchValidator(t *testing.T) {
+	path := writeFile(t, "config.json", `{"host":"localhost","port":8080}`)
+	errPort := errors.New("port is required")
+
+	w, err := Watch(context.Background(), path,
+		WithInterval[testConfig](time.Hour),
+		WithValidator(func(cfg *testConfig) error {
+			if cfg.Port == 0 {
+				return errPort
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"host":"changed"}`), 0o600))
+	assert.ErrorIs(t, w.Reload(), errPort)
+	assert.Equal(t, "localhost", w.Get().Host)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"host":"changed","port":9090}`), 0o600))
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, int64(9090), w.Get().Port)
+}
+
+func TestWatchInitialError(t *testing.T) {
+	_, err := Watch[testConfig](context.Background(), "missing.yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,318 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/anthanhphan/saturday/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// PageConfig configures how pagination parameters apply to a query. Only the
+// fields listed in Columns can be sorted and filtered by, so client input never
+// reaches the SQL text. Sort columns must not be nullable.
+type PageConfig struct {
+	Columns     map[string]string // Column of each field clients may use, e.g. {"created_at": "orders.created_at"}
+	Key         string            // Field of a unique column ending every sort so the order is total, e.g. "id"
+	DefaultSort []pagination.Sort // Sort of requests without one; Key ascending when empty
+	CountTotal  bool              // Count the matching rows into PageInfo.Total
+}
+
+// sortKey is a sort resolved to its column.
+type sortKey struct {
+	field  string
+	column string
+	desc   bool
+}
+
+// sortKeys returns the sort of params resolved to columns, ending with the key.
+// A cursor must have been made for the same sort.
+func (cfg PageConfig) sortKeys(params pagination.Params) ([]sortKey, error) {
+	sorts := params.Sort
+	if len(sorts) == 0 {
+		sorts = cfg.DefaultSort
+	}
+
+	keys := make([]sortKey, 0, len(sorts)+1)
+	hasKey := false
+	for _, sort := range sorts {
+		column, ok := cfg.Columns[sort.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %s", pagination.ErrInvalidParams, sort.Field)
+		}
+		keys = append(keys, sortKey{field: sort.Field, column: column, desc: sort.Desc})
+		hasKey = hasKey || sort.Field == cfg.Key
+	}
+	if !hasKey {
+		column, ok := cfg.Columns[cfg.Key]
+		if !ok {
+			return nil, fmt.Errorf("key field %s has no column", cfg.Key)
+		}
+		desc := len(keys) > 0 && keys[len(keys)-1].desc
+		keys = append(keys, sortKey{field: cfg.Key, column: column, desc: desc})
+	}
+
+	if params.Cursor != nil && !cursorMatches(*params.Cursor, keys) {
+		return nil, fmt.Errorf("%w: cursor does not match the sort", pagination.ErrInvalidParams)
+	}
+	return keys, nil
+}
+
+// cursorMatches reports whether cursor was made for the sort of keys.
+func cursorMatches(cursor pagination.Cursor, keys []sortKey) bool {
+	if len(cursor.Values) != len(keys) || len(cursor.Sort) != len(keys) {
+		return false
+	}
+	for i, key := range keys {
+		if cursor.Sort[i].Field != key.field || cursor.Sort[i].Desc != key.desc {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFilters reports filters on fields clients may not use.
+func (cfg PageConfig) checkFilters(params pagination.Params) error {
+	for _, filter := range params.Filters {
+		if _, ok := cfg.Columns[filter.Field]; !ok {
+			return fmt.Errorf("%w: unknown filter field %s", pagination.ErrInvalidParams, filter.Field)
+		}
+	}
+	return nil
+}
+
+// filterScope returns a scope applying the filters of params.
+func (cfg PageConfig) filterScope(params pagination.Params) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if err := cfg.checkFilters(params); err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+
+		for _, filter := range params.Filters {
+			col := clause.Column{Name: cfg.Columns[filter.Field]}
+			var expr clause.Expression
+			switch filter.Op {
+			case pagination.OpNe:
+				expr = clause.Neq{Column: col, Value: filter.Values[0]}
+			case pagination.OpGt:
+				expr = clause.Gt{Column: col, Value: filter.Values[0]}
+			case pagination.OpGte:
+				expr = clause.Gte{Column: col, Value: filter.Values[0]}
+			case pagination.OpLt:
+				expr = clause.Lt{Column: col, Value: filter.Values[0]}
+			case pagination.OpLte:
+				expr = clause.Lte{Column: col, Value: filter.Values[0]}
+			case pagination.OpIn:
+				values := make([]any, len(filter.Values))
+				for i, v := range filter.Values {
+					values[i] = v
+				}
+				expr = clause.IN{Column: col, Values: values}
+			default:
+				expr = clause.Eq{Column: col, Value: filter.Values[0]}
+			}
+			tx = tx.Clauses(clause.Where{Exprs: []clause.Expression{expr}})
+		}
+		return tx
+	}
+}
+
+// Paginate returns a scope applying pagination parameters to a query: the
+// filters, the sort, the keyset condition of the cursor or the offset of the
+// page, and a limit of one row more than the page so a next page can be
+// detected. Invalid parameters are added to the query errors, wrapping
+// pagination.ErrInvalidParams. FindPage runs the query and builds the page info.
+//
+// Parameters:
+//   - params: The parsed pagination parameters
+//   - cfg: The fields clients may use and the sort key
+//
+// Returns:
+//   - func(*gorm.DB) *gorm.DB: The scope
+//
+// Example:
+//
+//	var orders []Order
+//	err := db.Executor.WithContext(ctx).Where("tenant_id = ?", tenantId).
+//	    Scopes(db.Paginate(params, orderPageConfig)).Find(&orders).Error
+func (db *Database) Paginate(params pagination.Params, cfg PageConfig) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		keys, err := cfg.sortKeys(params)
+		if err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+
+		tx = cfg.filterScope(params)(tx)
+
+		backward := params.Cursor != nil && params.Cursor.Backward
+		if params.Cursor != nil {
+			tx = tx.Clauses(clause.Where{Exprs: []clause.Expression{keysetCondition(keys, params.Cursor.Values, backward)}})
+		}
+		for _, key := range keys {
+			// Backward pages are read in reverse order, then reversed by FindPage
+			tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: key.column}, Desc: key.desc != backward})
+		}
+		if params.Page > 0 {
+			tx = tx.Offset(params.Offset())
+		}
+		return tx.Limit(params.Limit + 1)
+	}
+}
+
+// keysetCondition builds the condition selecting rows after the cursor values
+// in the sort order, or before them when backward:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(keys []sortKey, values []any, backward bool) clause.Expression {
+	var sql strings.Builder
+	var vars []any
+	for i, key := range keys {
+		if i > 0 {
+			sql.WriteString(" OR ")
+		}
+		sql.WriteString("(")
+		for j := 0; j < i; j++ {
+			sql.WriteString("? = ? AND ")
+			vars = append(vars, clause.Column{Name: keys[j].column}, values[j])
+		}
+		op := ">"
+		if key.desc != backward {
+			op = "<"
+		}
+		sql.WriteString("? " + op + " ?)")
+		vars = append(vars, clause.Column{Name: key.column}, values[i])
+	}
+	return clause.Expr{SQL: "(" + sql.String() + ")", Vars: vars}
+}
+
+// FindPage finds a page of rows of query into dest, a pointer to a slice of
+// models, and describes it. Cursors of the page hold the sort values of its
+// first and last rows.
+//
+// Parameters:
+//   - ctx: Context of the query
+//   - query: Query to paginate, e.g. with conditions; db.Executor when nil
+//   - dest: Pointer to a slice of models receiving the rows
+//   - params: The parsed pagination parameters
+//   - cfg: The fields clients may use and the sort key
+//
+// Returns:
+//   - pagination.PageInfo: Description of the page
+//   - error: Error wrapping pagination.ErrInvalidParams if params do not fit cfg, or the query error
+//
+// Example:
+//
+//	var orderPageConfig = postgres.PageConfig{
+//	    Columns:     map[string]string{"id": "id", "created_at": "created_at", "status": "status"},
+//	    Key:         "id",
+//	    DefaultSort: []pagination.Sort{{Field: "created_at", Desc: true}},
+//	}
+//
+//	var orders []Order
+//	page, err := db.FindPage(ctx, db.Executor.Where("tenant_id = ?", tenantId), &orders, params, orderPageConfig)
+func (db *Database) FindPage(ctx context.Context, query *gorm.DB, dest any, params pagination.Params, cfg PageConfig) (pagination.PageInfo, error) {
+	if query == nil {
+		query = db.Executor
+	}
+	query = query.WithContext(ctx)
+
+	keys, err := cfg.sortKeys(params)
+	if err != nil {
+		return pagination.PageInfo{}, err
+	}
+	if err := cfg.checkFilters(params); err != nil {
+		return pagination.PageInfo{}, err
+	}
+
+	var total *int64
+	if cfg.CountTotal {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Model(dest).Scopes(cfg.filterScope(params)).Count(&count).Error; err != nil {
+			return pagination.PageInfo{}, fmt.Errorf("failed to count rows: %w", err)
+		}
+		total = &count
+	}
+
+	tx := query.Scopes(db.Paginate(params, cfg)).Find(dest)
+	if tx.Error != nil {
+		return pagination.PageInfo{}, fmt.Errorf("failed to find rows: %w", tx.Error)
+	}
+
+	page, err := newPageInfo(tx.Statement.Schema, reflect.ValueOf(dest).Elem(), keys, params)
+	if err != nil {
+		return pagination.PageInfo{}, err
+	}
+	page.Total = total
+	return page, nil
+}
+
+// newPageInfo trims the extra row of rows, restores the order of backward
+// pages and describes the page.
+func newPageInfo(s *schema.Schema, rows reflect.Value, keys []sortKey, params pagination.Params) (pagination.PageInfo, error) {
+	hasMore := rows.Len() > params.Limit
+	if hasMore {
+		rows.Set(rows.Slice(0, params.Limit))
+	}
+
+	page := pagination.PageInfo{Limit: params.Limit, Page: params.Page}
+	if params.Page > 0 {
+		page.HasNext = hasMore
+		page.HasPrev = params.Page > 1
+		return page, nil
+	}
+
+	if params.Cursor != nil && params.Cursor.Backward {
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+		page.HasNext, page.HasPrev = true, hasMore
+	} else {
+		page.HasNext, page.HasPrev = hasMore, params.Cursor != nil
+	}
+	if rows.Len() == 0 {
+		return page, nil
+	}
+
+	var err error
+	if page.HasNext {
+		if page.NextCursor, err = rowCursor(s, rows.Index(rows.Len()-1), keys, false); err != nil {
+			return pagination.PageInfo{}, err
+		}
+	}
+	if page.HasPrev {
+		if page.PrevCursor, err = rowCursor(s, rows.Index(0), keys, true); err != nil {
+			return pagination.PageInfo{}, err
+		}
+	}
+	return page, nil
+}
+
+// rowCursor encodes a cursor holding the sort values of row.
+func rowCursor(s *schema.Schema, row reflect.Value, keys []sortKey, backward bool) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("failed to build cursor: rows have no schema")
+	}
+	row = reflect.Indirect(row)
+
+	values := make([]any, len(keys))
+	sorts := make([]pagination.Sort, len(keys))
+	for i, key := range keys {
+		sorts[i] = pagination.Sort{Field: key.field, Desc: key.desc}
+		column := key.column
+		if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+		field := s.LookUpField(column)
+		if field == nil {
+			return "", fmt.Errorf("failed to build cursor: column %s is not a field of %s", column, s.Name)
+		}
+		values[i], _ = field.ValueOf(context.Background(), row)
+	}
+	return pagination.Cursor{Values: values, Sort: sorts, Backward: backward}.Encode()
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type testOrder struct {
+	ID        int64
+	Status    string
+	CreatedAt time.Time
+}
+
+var testPageConfig = PageConfig{
+	Columns:     map[string]string{"id": "id", "status": "status", "created_at": "created_at"},
+	Key:         "id",
+	DefaultSort: []pagination.Sort{{Field: "created_at", Desc: true}},
+}
+
+func newDryRunDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return &Database{Executor: db}
+}
+
+func TestPaginate(t *testing.T) {
+	db := newDryRunDatabase(t)
+	createdAt := "2024-05-01T10:00:00Z"
+	defaultSort := []pagination.Sort{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}}
+
+	tests := []struct {
+		name     string
+		params   pagination.Params
+		wantSQL  string
+		wantVars []any
+		wantErr  bool
+	}{
+		{
+			name:     "first page",
+			params:   pagination.Params{Limit: 10},
+			wantSQL:  `SELECT * FROM "test_orders" ORDER BY "created_at" DESC,"id" DESC LIMIT $1`,
+			wantVars: []any{11},
+		},
+		{
+			name:     "offset",
+			params:   pagination.Params{Page: 3, Limit: 10, Sort: []pagination.Sort{{Field: "status"}}},
+			wantSQL:  `SELECT * FROM "test_orders" ORDER BY "status","id" LIMIT $1 OFFSET $2`,
+			wantVars: []any{11, 20},
+		},
+		{
+			name: "next cursor with filters",
+			params: pagination.Params{
+				Limit:   10,
+				Cursor:  &pagination.Cursor{Values: []any{createdAt, int64(7)}, Sort: defaultSort},
+				Filters: []pagination.Filter{{Field: "status", Op: pagination.OpIn, Values: []string{"paid", "refunded"}}},
+			},
+			wantSQL: `SELECT * FROM "test_orders" WHERE "status" IN ($1,$2) AND ((("created_at" < $3) OR ("created_at" = $4 AND "id" < $5))) ` +
+				`ORDER BY "created_at" DESC,"id" DESC LIMIT $6`,
+			wantVars: []any{"paid", "refunded", createdAt, createdAt, int64(7), 11},
+		},
+		{
+			name: "previous cursor",
+			params: pagination.Params{
+				Limit:  10,
+				Cursor: &pagination.Cursor{Values: []any{createdAt, int64(7)}, Sort: defaultSort, Backward: true},
+			},
+			wantSQL: `SELECT * FROM "test_orders" WHERE (("created_at" > $1) OR ("created_at" = $2 AND "id" > $3)) ` +
+				`ORDER BY "created_at","id" LIMIT $4`,
+			wantVars: []any{createdAt, createdAt, int64(7), 11},
+		},
+		{
+			name:    "unknown sort field",
+			params:  pagination.Params{Limit: 10, Sort: []pagination.Sort{{Field: "password"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown filter field",
+			params:  pagination.Params{Limit: 10, Filters: []pagination.Filter{{Field: "password", Op: pagination.OpEq, Values: []string{"x"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "cursor of another sort",
+			params:  pagination.Params{Limit: 10, Cursor: &pagination.Cursor{Values: []any{int64(7)}, Sort: []pagination.Sort{{Field: "id"}}}},
+			wantErr: true,
+		},
+		{
+			name: "cursor of another sort direction",
+			params: pagination.Params{
+				Limit:  10,
+				Cursor: &pagination.Cursor{Values: []any{createdAt, int64(7)}, Sort: []pagination.Sort{{Field: "created_at"}, {Field: "id"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cursor of another sort field",
+			params: pagination.Params{
+				Limit:  10,
+				Sort:   []pagination.Sort{{Field: "status", Desc: true}},
+				Cursor: &pagination.Cursor{Values: []any{createdAt, int64(7)}, Sort: defaultSort},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "cursor without sort",
+			params:  pagination.Params{Limit: 10, Cursor: &pagination.Cursor{Values: []any{createdAt, int64(7)}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var orders []testOrder
+			tx := db.Executor.Scopes(db.Paginate(tt.params, testPageConfig)).Find(&orders)
+			if tt.wantErr {
+				assert.ErrorIs(t, tx.Error, pagination.ErrInvalidParams)
+				return
+			}
+			require.NoError(t, tx.Error)
+			assert.Equal(t, tt.wantSQL, tx.Statement.SQL.String())
+			assert.Equal(t, tt.wantVars, tx.Statement.Vars)
+		})
+	}
+}
+
+func TestNewPageInfo(t *testing.T) {
+	s, err := schema.Parse(&testOrder{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	keys, err := testPageConfig.sortKeys(pagination.Params{})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	newOrders := func(ids ...int64) []testOrder {
+		orders := make([]testOrder, len(ids))
+		for i, id := range ids {
+			orders[i] = testOrder{ID: id, CreatedAt: createdAt}
+		}
+		return orders
+	}
+	cursorOf := func(id int64, backward bool) string {
+		cursor, err := pagination.Cursor{
+			Values:   []any{createdAt, id},
+			Sort:     []pagination.Sort{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}},
+			Backward: backward,
+		}.Encode()
+		require.NoError(t, err)
+		return cursor
+	}
+
+	tests := []struct {
+		name    string
+		rows    []testOrder
+		params  pagination.Params
+		wantIds []int64
+		want    pagination.PageInfo
+	}{
+		{
+			name:    "first page",
+			rows:    newOrders(9, 8, 7),
+			params:  pagination.Params{Limit: 2},
+			wantIds: []int64{9, 8},
+			want:    pagination.PageInfo{Limit: 2, HasNext: true, NextCursor: cursorOf(8, false)},
+		},
+		{
+			name:    "last page",
+			rows:    newOrders(7),
+			params:  pagination.Params{Limit: 2, Cursor: &pagination.Cursor{}},
+			wantIds: []int64{7},
+			want:    pagination.PageInfo{Limit: 2, HasPrev: true, PrevCursor: cursorOf(7, true)},
+		},
+		{
+			name:    "backward page",
+			rows:    newOrders(5, 6, 7),
+			params:  pagination.Params{Limit: 2, Cursor: &pagination.Cursor{Backward: true}},
+			wantIds: []int64{6, 5},
+			want: pagination.PageInfo{
+				Limit: 2, HasNext: true, HasPrev: true,
+				NextCursor: cursorOf(5, false), PrevCursor: cursorOf(6, true),
+			},
+		},
+		{
+			name:    "offset page",
+			rows:    newOrders(5, 4, 3),
+			params:  pagination.Params{Page: 2, Limit: 2},
+			wantIds: []int64{5, 4},
+			want:    pagination.PageInfo{Limit: 2, Page: 2, HasNext: true, HasPrev: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			page, err := newPageInfo(s, reflect.ValueOf(&rows).Elem(), keys, tt.params)
+			require.NoError(t, err)
+
+			var ids []int64
+			for _, row := range rows {
+				ids = append(ids, row.ID)
+			}
+			assert.Equal(t, tt.wantIds, ids)
+			assert.Equal(t, tt.want, page)
+		})
+	}
+}
+
+func TestFindPageInvalidParams(t *testing.T) {
+	db := newDryRunDatabase(t)
+
+	var orders []testOrder
+	_, err := db.FindPage(context.Background(), nil, &orders, pagination.Params{Limit: 10, Sort: []pagination.Sort{{Field: "password"}}}, testPageConfig)
+	assert.ErrorIs(t, err, pagination.ErrInvalidParams)
+}
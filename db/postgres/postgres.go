@@ -50,6 +50,11 @@ func NewDatabase(conn Connection, logLevel string, slowQueryThreshold int64) (*D
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
+	// Record queries in spans and metrics
+	if err := db.Use(gzlog.Instrumentation{}); err != nil {
+		return nil, fmt.Errorf("failed to instrument database: %w", err)
+	}
+
 	// Retrieve the underlying *sql.DB instance
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -91,3 +96,19 @@ func createLogger(logLevel string, slowQueryThreshold int64) logger.Interface {
 
 	return gzlog.NewGormLogger(level, slowQueryThreshold)
 }
+
+// Close closes the underlying connection pool.
+//
+// Returns:
+//   - error: Any error encountered while closing the pool
+//
+// Example:
+//
+//	defer db.Close()
+func (db *Database) Close() error {
+	sqlDB, err := db.Executor.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQL DB instance: %w", err)
+	}
+	return sqlDB.Close()
+}
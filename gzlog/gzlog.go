@@ -2,13 +2,27 @@ package gzlog
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	saturdaylogger "github.com/anthanhphan/saturday/logger"
+	"github.com/anthanhphan/saturday/metrics"
+	"github.com/anthanhphan/saturday/tracing"
 )
 
+// beginKey is the statement setting holding the start time of a query.
+const beginKey = "gzlog:begin"
+
 type ContextFn func(ctx context.Context) []zapcore.Field
 
 type GormLogger struct {
@@ -42,6 +56,9 @@ func NewGormLogger(level logger.LogLevel, slowThreshold int64) GormLogger {
 }
 
 // Trace logs SQL execution time and query details based on configured thresholds.
+// Lines carry the request ID and trace ID of ctx, so queries run with
+// db.WithContext(ctx) can be correlated with the request that issued them.
+// Spans and metrics of queries are recorded by Instrumentation.
 //
 // Parameters:
 //   - ctx: Context for the operation
@@ -55,21 +72,21 @@ func NewGormLogger(level logger.LogLevel, slowThreshold int64) GormLogger {
 //	    return "SELECT * FROM users", 10
 //	}, nil)
 func (gl GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
 	if gl.LogLevel <= 0 {
 		return
 	}
 
-	elapsed := time.Since(begin)
 	switch {
 	case err != nil && gl.LogLevel >= logger.Error:
 		sql, rows := fc()
-		zap.L().Sugar().Error("[TRACE] ", zap.Error(err), zap.Duration("duration", elapsed), zap.Int64("rows", rows), zap.String("sql query", sql))
+		saturdaylogger.FromContext(ctx).Sugar().Error("[TRACE] ", zap.Error(err), zap.Duration("duration", elapsed), zap.Int64("rows", rows), zap.String("sql query", sql))
 	case gl.SlowThreshold != 0 && elapsed > gl.SlowThreshold && gl.LogLevel >= logger.Warn:
 		sql, rows := fc()
-		zap.L().Sugar().Warn("[TRACE] ", zap.Duration("duration", elapsed), zap.Int64("rows", rows), zap.String("sql query", sql))
+		saturdaylogger.FromContext(ctx).Sugar().Warn("[TRACE] ", zap.Duration("duration", elapsed), zap.Int64("rows", rows), zap.String("sql query", sql))
 	case gl.LogLevel >= logger.Info:
 		sql, rows := fc()
-		zap.L().Sugar().Info("[TRACE] ", zap.Duration("duration", elapsed), zap.Int64("rows", rows), zap.String("sql query", sql))
+		saturdaylogger.FromContext(ctx).Sugar().Info("[TRACE] ", zap.Duration("duration", elapsed), zap.Int64("rows", rows), zap.String("sql query", sql))
 	}
 }
 
@@ -85,7 +102,7 @@ func (gl GormLogger) Info(ctx context.Context, str string, args ...interface{})
 		return
 	}
 
-	zap.L().Sugar().Infof(str, args...)
+	saturdaylogger.FromContext(ctx).Sugar().Infof(str, args...)
 }
 
 func (gl GormLogger) Warn(ctx context.Context, str string, args ...interface{}) {
@@ -93,7 +110,7 @@ func (gl GormLogger) Warn(ctx context.Context, str string, args ...interface{})
 		return
 	}
 
-	zap.L().Sugar().Warnf(str, args...)
+	saturdaylogger.FromContext(ctx).Sugar().Warnf(str, args...)
 }
 
 func (gl GormLogger) Error(ctx context.Context, str string, args ...interface{}) {
@@ -101,5 +118,109 @@ func (gl GormLogger) Error(ctx context.Context, str string, args ...interface{})
 		return
 	}
 
-	zap.L().Sugar().Errorf(str, args...)
+	saturdaylogger.FromContext(ctx).Sugar().Errorf(str, args...)
+}
+
+// Instrumentation is a gorm.Plugin recording every query as a client span when
+// tracing is enabled, and in the query duration metrics when metrics are
+// enabled. Spans carry the parameterized SQL, so bound values are never exported.
+//
+// Example:
+//
+//	if err := db.Use(gzlog.Instrumentation{}); err != nil {
+//	    log.Fatal(err)
+//	}
+type Instrumentation struct{}
+
+// Name returns the name of the plugin.
+func (Instrumentation) Name() string {
+	return "gzlog:instrumentation"
+}
+
+// Initialize registers the callbacks timing every query run by db.
+//
+// Parameters:
+//   - db: The database the plugin is used by
+//
+// Returns:
+//   - error: Error if a callback cannot be registered
+func (Instrumentation) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	err := errors.Join(
+		callback.Create().Before("*").Register("gzlog:before_create", startQuery),
+		callback.Create().After("*").Register("gzlog:after_create", finishQuery),
+		callback.Query().Before("*").Register("gzlog:before_query", startQuery),
+		callback.Query().After("*").Register("gzlog:after_query", finishQuery),
+		callback.Update().Before("*").Register("gzlog:before_update", startQuery),
+		callback.Update().After("*").Register("gzlog:after_update", finishQuery),
+		callback.Delete().Before("*").Register("gzlog:before_delete", startQuery),
+		callback.Delete().After("*").Register("gzlog:after_delete", finishQuery),
+		callback.Row().Before("*").Register("gzlog:before_row", startQuery),
+		callback.Row().After("*").Register("gzlog:after_row", finishQuery),
+		callback.Raw().Before("*").Register("gzlog:before_raw", startQuery),
+		callback.Raw().After("*").Register("gzlog:after_raw", finishQuery),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register query callbacks: %w", err)
+	}
+	return nil
+}
+
+// startQuery records when the query of db starts.
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(beginKey, time.Now())
+}
+
+// finishQuery records the finished query of db in the span and metrics.
+func finishQuery(db *gorm.DB) {
+	stmt := db.Statement
+	if db.DryRun || stmt.SQL.Len() == 0 {
+		return
+	}
+	value, ok := db.InstanceGet(beginKey)
+	if !ok {
+		return
+	}
+	begin := value.(time.Time)
+
+	sql := stmt.SQL.String()
+	traceQuery(stmt.Context, begin, sql, db.RowsAffected, db.Error)
+	if m := metrics.Global(); m != nil {
+		m.ObserveQuery(operation(sql), time.Since(begin), queryError(db.Error))
+	}
+}
+
+// traceQuery records a finished query as a client span started at begin.
+func traceQuery(ctx context.Context, begin time.Time, sql string, rows int64, err error) {
+	_, span := tracing.Start(ctx, operation(sql), trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(begin))
+	if !span.IsRecording() {
+		span.End()
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("db.query.text", sql),
+		attribute.Int64("db.response.returned_rows", rows),
+	)
+	if err = queryError(err); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// operation returns the SQL operation of a query, e.g. "SELECT".
+func operation(sql string) string {
+	if operation, _, _ := strings.Cut(strings.TrimSpace(sql), " "); operation != "" {
+		return strings.ToUpper(operation)
+	}
+	return "db.query"
+}
+
+// queryError returns err unless it only reports that no record was found.
+func queryError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
 }
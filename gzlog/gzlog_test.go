@@ -0,0 +1,46 @@
+package gzlog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anthanhphan/saturday/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestInstrumentation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, undo := tracing.InitTracer(&tracing.Config{ServiceName: "test"}, exporter)
+	defer undo()
+
+	// Nothing listens on the port, so the query fails after its SQL is built
+	dialector := postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1"})
+	db, err := gorm.Open(dialector, &gorm.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Instrumentation{}))
+
+	var user struct{ Email string }
+	err = db.WithContext(context.Background()).Table("users").Where("email = ?", "secret@example.com").Take(&user).Error
+	require.Error(t, err)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "SELECT", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+
+	var text string
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "db.query.text" {
+			text = attr.Value.AsString()
+		}
+	}
+	assert.Contains(t, text, "email = $1")
+	assert.NotContains(t, text, "secret@example.com")
+}
@@ -0,0 +1,177 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/constant/method"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const policyYAML = `
+roles:
+  viewer:
+    permissions: ["orders:read"]
+  customer:
+    inherits: [viewer]
+    permissions:
+      - orders:update if owner
+  editor:
+    inherits: [viewer]
+    permissions: ["orders:*"]
+  archivist:
+    permissions: ["orders*"]
+  admin:
+    inherits: [editor]
+    permissions: ["*"]
+`
+
+func loadTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(policyYAML), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	policy.RegisterCondition("owner", Owner("user_id"))
+	return policy
+}
+
+func TestIsAllowed(t *testing.T) {
+	policy := loadTestPolicy(t)
+
+	tests := []struct {
+		name       string
+		requester  requester.CtxRequester
+		permission string
+		want       bool
+	}{
+		{"direct grant", requester.NewCtxRequester(1, requester.SetRoles("viewer")), "orders:read", true},
+		{"missing grant", requester.NewCtxRequester(1, requester.SetRoles("viewer")), "orders:update", false},
+		{"inherited grant", requester.NewCtxRequester(1, requester.SetRoles("customer")), "orders:read", true},
+		{"condition holds", requester.NewCtxRequester(7, requester.SetRoles("customer")), "orders:update", true},
+		{"condition fails", requester.NewCtxRequester(8, requester.SetRoles("customer")), "orders:update", false},
+		{"prefix wildcard", requester.NewCtxRequester(1, requester.SetRoles("editor")), "orders:delete", true},
+		{"prefix wildcard mismatch", requester.NewCtxRequester(1, requester.SetRoles("editor")), "users:delete", false},
+		{"prefix wildcard of another resource", requester.NewCtxRequester(1, requester.SetRoles("editor")), "orders-archive:delete", false},
+		{"wildcard without colon", requester.NewCtxRequester(1, requester.SetRoles("archivist")), "orders-archive:delete", false},
+		{"global wildcard", requester.NewCtxRequester(1, requester.SetRoles("admin")), "users:delete", true},
+		{"scope", requester.NewCtxRequester(1, requester.SetScopes("users:read")), "users:read", true},
+		{"unknown role", requester.NewCtxRequester(1, requester.SetRoles("ghost")), "orders:read", false},
+		{"nil requester", nil, "orders:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Params = gin.Params{{Key: "user_id", Value: "7"}}
+			assert.Equal(t, tt.want, policy.IsAllowed(ctx, tt.requester, tt.permission))
+		})
+	}
+}
+
+func TestNewPolicyErrors(t *testing.T) {
+	_, err := NewPolicy(PolicyConfig{Roles: map[string]RoleConfig{"a": {Inherits: []string{"b"}}}})
+	assert.EqualError(t, err, "role a inherits from unknown role b")
+
+	_, err = NewPolicy(PolicyConfig{Roles: map[string]RoleConfig{"a": {Permissions: []string{" if owner"}}}})
+	assert.EqualError(t, err, "role a has an empty permission")
+
+	policy, err := NewPolicy(PolicyConfig{Roles: map[string]RoleConfig{
+		"a": {Inherits: []string{"b"}, Permissions: []string{"x"}},
+		"b": {Inherits: []string{"a"}, Permissions: []string{"y"}},
+	}})
+	require.NoError(t, err)
+	assert.True(t, policy.IsAllowed(nil, requester.NewCtxRequester(1, requester.SetRoles("a")), "y"))
+}
+
+func TestProtect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policy := loadTestPolicy(t)
+
+	var roles []string
+	authenticate := func(ctx *gin.Context) {
+		if roles != nil {
+			ctx.Set(string(ctxkey.CtxRequesterKey), requester.NewCtxRequester(7, requester.SetRoles(roles...)))
+		}
+	}
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) }
+
+	routes := Protect(policy, []route.Route{
+		{Path: "/public", Method: method.GET, Handler: ok},
+		{Path: "/orders", Method: method.POST, Handler: ok, Permissions: []string{"orders:create"}},
+		{
+			Path: "/reports", Method: method.GET, Handler: ok, Permissions: []string{"orders:read"},
+			Middlewares: []func(*gin.Context){func(ctx *gin.Context) {
+				ctx.Set(string(ctxkey.CtxRequesterKey), requester.NewCtxRequester(1, requester.SetRoles("viewer")))
+			}},
+		},
+	})
+	groups := ProtectGroups(policy, []route.GroupRoute{{
+		Prefix:      "/users/:user_id",
+		Middlewares: []func(*gin.Context){authenticate},
+		Permissions: []string{"orders:read"},
+		Routes: []route.Route{
+			{Path: "/orders", Method: method.GET, Handler: ok},
+			{Path: "/orders", Method: method.PUT, Handler: ok, Permissions: []string{"orders:update"}},
+		},
+	}})
+
+	engine := route.NewGinEngine(
+		route.AddMiddlewares(authenticate),
+		route.AddRoutes(routes),
+		route.AddGroupRoutes(groups),
+	)
+
+	tests := []struct {
+		name       string
+		roles      []string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"public", nil, http.MethodGet, "/public", http.StatusNoContent},
+		{"unauthenticated", nil, http.MethodPost, "/orders", http.StatusUnauthorized},
+		{"forbidden", []string{"viewer"}, http.MethodPost, "/orders", http.StatusForbidden},
+		{"allowed", []string{"editor"}, http.MethodPost, "/orders", http.StatusNoContent},
+		{"route authentication", nil, http.MethodGet, "/reports", http.StatusNoContent},
+		{"group permission", []string{"viewer"}, http.MethodGet, "/users/1/orders", http.StatusNoContent},
+		{"group permission denied", []string{}, http.MethodGet, "/users/1/orders", http.StatusForbidden},
+		{"owner", []string{"customer"}, http.MethodPut, "/users/7/orders", http.StatusNoContent},
+		{"not owner", []string{"customer"}, http.MethodPut, "/users/8/orders", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles = tt.roles
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func TestProtectWithoutPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := route.NewGinEngine(
+		route.AddMiddlewares(func(ctx *gin.Context) {
+			ctx.Set(string(ctxkey.CtxRequesterKey), requester.NewCtxRequester(1, requester.SetRoles("admin")))
+		}),
+		route.AddRoutes(Protect(nil, []route.Route{
+			{Path: "/orders", Method: method.GET, Handler: func(*gin.Context) {}, Permissions: []string{"orders:read"}},
+		})),
+	)
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
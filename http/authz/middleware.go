@@ -0,0 +1,99 @@
+package authz
+
+import (
+	"fmt"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+)
+
+// Require creates a middleware that lets the request through only if the
+// requester stored by middlewares.Auth holds every permission. Requests without
+// a requester abort with 401, denied requests with 403.
+//
+// Parameters:
+//   - permissions: Required permissions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function authorizing the request
+//
+// Example:
+//
+//	router.DELETE("/orders/:id", middlewares.Auth(j), policy.Require("orders:delete"), deleteOrder)
+func (p *Policy) Require(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		r := requesterFrom(ctx)
+		if r == nil {
+			resp.AbortWithError(ctx, resp.ErrUnauthenticated(nil))
+			return
+		}
+
+		for _, permission := range permissions {
+			if p == nil || !p.IsAllowed(ctx, r, permission) {
+				resp.AbortWithError(ctx, resp.ErrForbidden(fmt.Errorf("missing permission %s", permission)))
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// Protect returns copies of routes whose declared Permissions are enforced by
+// p.Require. Route checks run after the global, group and route middlewares, so
+// the authentication middleware may be any of them. A nil policy denies every
+// route that declares permissions.
+//
+// Parameters:
+//   - p: The policy
+//   - routes: Routes to protect
+//
+// Returns:
+//   - []route.Route: The protected routes
+func Protect(p *Policy, routes []route.Route) []route.Route {
+	protected := make([]route.Route, len(routes))
+	for i, r := range routes {
+		if len(r.Permissions) > 0 {
+			r.Middlewares = append(append([]func(*gin.Context){}, r.Middlewares...), p.Require(r.Permissions...))
+		}
+		protected[i] = r
+	}
+	return protected
+}
+
+// ProtectGroups returns copies of groups whose group and route Permissions are
+// enforced by p.Require.
+//
+// Parameters:
+//   - p: The policy
+//   - groups: Groups to protect
+//
+// Returns:
+//   - []route.GroupRoute: The protected groups
+func ProtectGroups(p *Policy, groups []route.GroupRoute) []route.GroupRoute {
+	protected := make([]route.GroupRoute, len(groups))
+	for i, group := range groups {
+		if len(group.Permissions) > 0 {
+			group.Middlewares = append(append([]func(*gin.Context){}, group.Middlewares...), p.Require(group.Permissions...))
+		}
+		group.Routes = Protect(p, group.Routes)
+		protected[i] = group
+	}
+	return protected
+}
+
+// requesterFrom returns the requester stored in the gin or request context.
+func requesterFrom(ctx *gin.Context) requester.CtxRequester {
+	if value, ok := ctx.Get(string(ctxkey.CtxRequesterKey)); ok {
+		if r, ok := value.(requester.CtxRequester); ok {
+			return r
+		}
+	}
+	if r, err := metadata.GetRequester(ctx.Request.Context()); err == nil {
+		return r
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anthanhphan/saturday/config"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// conditionSeparator separates a permission from its condition, e.g. "orders:update if owner".
+const conditionSeparator = " if "
+
+// PolicyConfig is the file representation of a Policy.
+//
+// Fields:
+//   - Roles: Roles by name
+//
+// Example (YAML):
+//
+//	roles:
+//	  viewer:
+//	    permissions: ["orders:read"]
+//	  customer:
+//	    inherits: [viewer]
+//	    permissions: ["orders:update if owner"]
+//	  admin:
+//	    permissions: ["*"]
+type PolicyConfig struct {
+	Roles map[string]RoleConfig `yaml:"roles" json:"roles" validate:"required"`
+}
+
+// RoleConfig describes a role.
+//
+// Fields:
+//   - Inherits: Roles whose permissions this role also grants
+//   - Permissions: Granted permissions. "orders:*" grants every orders permission,
+//     "*" grants everything, and an "if <condition>" suffix grants the permission
+//     only when the named condition holds
+type RoleConfig struct {
+	Inherits    []string `yaml:"inherits" json:"inherits"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+}
+
+// Condition is an attribute rule evaluated for a request, e.g. "the requester owns the resource".
+type Condition func(ctx *gin.Context, r requester.CtxRequester) bool
+
+type grant struct {
+	permission string
+	condition  string
+}
+
+// Policy decides which requesters hold which permissions. Permissions come from
+// the requester's roles, including inherited roles, and from its scopes.
+type Policy struct {
+	roles map[string][]grant
+
+	mu         sync.RWMutex
+	conditions map[string]Condition
+}
+
+// NewPolicy creates a Policy from its configuration.
+//
+// Parameters:
+//   - cfg: The policy configuration
+//
+// Returns:
+//   - *Policy: The policy
+//   - error: Error if a role inherits from an unknown role or a permission is empty
+//
+// Example:
+//
+//	policy, err := authz.NewPolicy(authz.PolicyConfig{Roles: map[string]authz.RoleConfig{
+//	    "viewer": {Permissions: []string{"orders:read"}},
+//	    "editor": {Inherits: []string{"viewer"}, Permissions: []string{"orders:write"}},
+//	}})
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	p := &Policy{
+		roles:      make(map[string][]grant, len(cfg.Roles)),
+		conditions: map[string]Condition{},
+	}
+
+	for name, role := range cfg.Roles {
+		for _, parent := range role.Inherits {
+			if _, ok := cfg.Roles[parent]; !ok {
+				return nil, fmt.Errorf("role %s inherits from unknown role %s", name, parent)
+			}
+		}
+	}
+
+	for name := range cfg.Roles {
+		grants, err := collectGrants(cfg.Roles, name, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		p.roles[name] = grants
+	}
+
+	return p, nil
+}
+
+// LoadPolicy reads a PolicyConfig through the config package and creates a Policy.
+//
+// Parameters:
+//   - path: Path of the policy file (YAML, JSON, TOML, ...)
+//   - opts: Options passed to config.NewConfig
+//
+// Returns:
+//   - *Policy: The policy
+//   - error: Error if the file cannot be loaded or the policy is invalid
+//
+// Example:
+//
+//	policy, err := authz.LoadPolicy("./env/policy.yaml")
+func LoadPolicy(path string, opts ...config.Option) (*Policy, error) {
+	cfg, err := config.NewConfig(path, &PolicyConfig{}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy: %w", err)
+	}
+	return NewPolicy(*cfg)
+}
+
+// collectGrants returns the grants of a role and of the roles it inherits from.
+// Inheritance cycles are ignored.
+func collectGrants(roles map[string]RoleConfig, name string, seen map[string]bool) ([]grant, error) {
+	if seen[name] {
+		return nil, nil
+	}
+	seen[name] = true
+
+	role := roles[name]
+	grants := make([]grant, 0, len(role.Permissions))
+	for _, entry := range role.Permissions {
+		permission, condition, _ := strings.Cut(entry, conditionSeparator)
+		permission, condition = strings.TrimSpace(permission), strings.TrimSpace(condition)
+		if permission == "" {
+			return nil, fmt.Errorf("role %s has an empty permission", name)
+		}
+		grants = append(grants, grant{permission: permission, condition: condition})
+	}
+
+	for _, parent := range role.Inherits {
+		inherited, err := collectGrants(roles, parent, seen)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, inherited...)
+	}
+	return grants, nil
+}
+
+// RegisterCondition registers a named condition used by "<permission> if <name>" grants.
+//
+// Parameters:
+//   - name: Condition name
+//   - condition: The attribute rule
+//
+// Example:
+//
+//	policy.RegisterCondition("owner", authz.Owner("user_id"))
+//	policy.RegisterCondition("same_tenant", authz.SameTenant("tenant_id"))
+func (p *Policy) RegisterCondition(name string, condition Condition) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.conditions[name] = condition
+}
+
+// IsAllowed reports whether r holds permission for the request in ctx.
+//
+// Parameters:
+//   - ctx: The request, used by conditions
+//   - r: The requester
+//   - permission: The permission, e.g. "orders:read"
+//
+// Returns:
+//   - bool: true if one of r's scopes or role grants matches
+func (p *Policy) IsAllowed(ctx *gin.Context, r requester.CtxRequester, permission string) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, scope := range r.GetScopes() {
+		if matchPermission(scope, permission) {
+			return true
+		}
+	}
+
+	for _, role := range r.GetRoles() {
+		for _, g := range p.roles[role] {
+			if !matchPermission(g.permission, permission) {
+				continue
+			}
+			if g.condition == "" || p.check(ctx, r, g.condition) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check evaluates a named condition. Unknown conditions deny.
+func (p *Policy) check(ctx *gin.Context, r requester.CtxRequester, name string) bool {
+	p.mu.RLock()
+	condition, ok := p.conditions[name]
+	p.mu.RUnlock()
+
+	if !ok {
+		zap.L().With(zap.String("prefix", "IsAllowed")).Sugar().Warnf("unknown authorization condition: %s", name)
+		return false
+	}
+	return condition(ctx, r)
+}
+
+// matchPermission reports whether granted covers permission. "*" matches
+// everything and a trailing ":*" matches every permission with that prefix.
+func matchPermission(granted, permission string) bool {
+	if granted == "*" || granted == permission {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(granted, ":*"); ok {
+		return strings.HasPrefix(permission, prefix+":")
+	}
+	return false
+}
+
+// Owner returns a Condition that holds when the path parameter param equals the requester's user ID.
+//
+// Parameters:
+//   - param: Path parameter holding the owner's user ID
+//
+// Returns:
+//   - Condition: The condition
+func Owner(param string) Condition {
+	return func(ctx *gin.Context, r requester.CtxRequester) bool {
+		value := ctx.Param(param)
+		return value != "" && r.GetUserId() != nil && value == fmt.Sprint(r.GetUserId())
+	}
+}
+
+// SameTenant returns a Condition that holds when the path parameter param equals the requester's tenant ID.
+//
+// Parameters:
+//   - param: Path parameter holding the tenant ID
+//
+// Returns:
+//   - Condition: The condition
+func SameTenant(param string) Condition {
+	return func(ctx *gin.Context, r requester.CtxRequester) bool {
+		value := ctx.Param(param)
+		return value != "" && r.GetTenantId() != nil && value == fmt.Sprint(r.GetTenantId())
+	}
+}
@@ -3,6 +3,7 @@ package ctxkey
 type ctxKeyType string
 
 const (
-	CtxRequestIdKey ctxKeyType = "X-Request-ID"
-	CtxRequesterKey ctxKeyType = "CONTEXT_REQUESTER"
+	CtxRequestIdKey   ctxKeyType = "X-Request-ID"
+	CtxRequesterKey   ctxKeyType = "CONTEXT_REQUESTER"
+	CtxTraceParentKey ctxKeyType = "traceparent"
 )
@@ -11,3 +11,25 @@ const (
 	HEAD
 	OPTIONS
 )
+
+// String returns the HTTP method name, e.g. "GET", or an empty string for unknown values.
+func (m Method) String() string {
+	switch m {
+	case GET:
+		return "GET"
+	case POST:
+		return "POST"
+	case PUT:
+		return "PUT"
+	case PATCH:
+		return "PATCH"
+	case DELETE:
+		return "DELETE"
+	case HEAD:
+		return "HEAD"
+	case OPTIONS:
+		return "OPTIONS"
+	default:
+		return ""
+	}
+}
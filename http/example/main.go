@@ -2,16 +2,13 @@ package main
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/anthanhphan/saturday/http/constant/method"
 	"github.com/anthanhphan/saturday/http/middlewares"
+	"github.com/anthanhphan/saturday/http/openapi"
 	"github.com/anthanhphan/saturday/http/resp"
 	"github.com/anthanhphan/saturday/http/route"
 	"github.com/anthanhphan/saturday/http/server"
@@ -20,57 +17,6 @@ import (
 	"go.uber.org/zap"
 )
 
-func Start(srv *server.HttpServer) {
-	log := zap.L().With(zap.String("prefix", "start")).Sugar()
-
-	srv.Middlewares = append(srv.Middlewares, middlewares.RequestId())
-	srv.Middlewares = append(srv.Middlewares, middlewares.Recover())
-
-	// Setup route
-	r := route.NewGinEngine(
-		route.AddMiddlewares(srv.Middlewares...),
-		route.AddHealthCheckRoute(),
-		route.AddRouteNotFoundHandler(),
-		route.SetStrictSlash(srv.StrictSlash),
-		route.SetMaximumMultipartSize(10000000),
-		route.AddGroupRoutes(srv.GroupRoutes),
-		route.AddRoutes(srv.Routes),
-		route.AddGinOptions(srv.GinOptions...),
-	)
-
-	// Create an HTTP server instance with the Gin engine
-	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", srv.Port),
-		Handler: r,
-	}
-
-	// Channel to listen for interrupt signals (e.g., CTRL+C, SIGTERM)
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// Start the server in a separate goroutine
-	go func() {
-		log.Infof("http server started on port %v", srv.Port)
-		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("could not start server: %v\n", err)
-		}
-	}()
-
-	// Wait for interrupt signal
-	<-quit
-	log.Infof("shutting down server...")
-
-	// Graceful shutdown with a timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Fatalf("server forced to shutdown: %v", err)
-	}
-
-	log.Infof("server exited gracefully")
-}
-
 func main() {
 	logInstance, undo := logger.InitLogger(&logger.Config{
 		DisableCaller:     false,
@@ -88,7 +34,15 @@ func main() {
 		server.AddPort(int64(5000)),
 		server.AddName("Test Server"),
 		server.SetStrictSlash(true),
-		server.SetGracefulShutdownTimeout(time.Duration(10000)),
+		server.SetGracefulShutdownTimeout(10*time.Second),
+		server.SetReadHeaderTimeout(5*time.Second),
+		server.SetIdleTimeout(time.Minute),
+		server.AddGinOptions(route.SetMaximumMultipartSize(10000000)),
+		server.SetOpenAPI(&openapi.Config{Title: "Test Server", Version: "1.0.0"}),
+		server.SetTracing(middlewares.TracingSkipPaths("/health-check")),
+		server.SetAccessLog(middlewares.AccessLogSkipPaths("/health-check")),
+		server.SetCompression(),
+		server.SetETag(),
 	)
 
 	httpServer.AddRoutes([]route.Route{
@@ -96,12 +50,37 @@ func main() {
 			Path:    "/hello-world",
 			Method:  method.GET,
 			Handler: SayHelloWorld,
+			Doc: &route.Doc{
+				Summary: "Say hello",
+				Tags:    []string{"greeting"},
+			},
 		},
+		route.TypedRoute(method.GET, "/hello/:name", SayHello, &route.Doc{
+			Summary: "Say hello to someone",
+			Tags:    []string{"greeting"},
+		}),
 	})
 
-	Start(httpServer)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := httpServer.Start(ctx); err != nil {
+		zap.S().Fatalf("http server failed: %v", err)
+	}
 }
 
 func SayHelloWorld(ctx *gin.Context) {
 	resp.ResponseSuccess(ctx, resp.NewSuccessResp("Hello World!", nil))
 }
+
+type SayHelloReq struct {
+	Name string `uri:"name" validate:"required,max=32"`
+}
+
+type SayHelloRes struct {
+	Greeting string `json:"greeting"`
+}
+
+func SayHello(_ context.Context, req SayHelloReq) (SayHelloRes, error) {
+	return SayHelloRes{Greeting: "Hello " + req.Name + "!"}, nil
+}
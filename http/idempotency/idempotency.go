@@ -0,0 +1,331 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/anthanhphan/saturday/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Idempotency headers
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderReplayed       = "Idempotent-Replayed"
+)
+
+const (
+	defaultTTL         = 24 * time.Hour
+	defaultLockTimeout = time.Minute
+	defaultMaxBodySize = 1 << 20
+	maxKeyLength       = 255
+	tokenLength        = 16
+)
+
+// unstoredHeaders are response headers describing the transfer rather than the
+// response, which are set again when it is replayed.
+var unstoredHeaders = []string{"Content-Encoding", "Content-Length", "Vary", "Date"}
+
+// Option is a function type that modifies the idempotency middleware configuration.
+type Option func(*options)
+
+type options struct {
+	header      string
+	ttl         time.Duration
+	lockTimeout time.Duration
+	maxBodySize int64
+	required    bool
+}
+
+// WithHeader returns an Option that reads the key from another header. Defaults
+// to Idempotency-Key.
+//
+// Parameters:
+//   - header: Header holding the key
+//
+// Returns:
+//   - Option: Function that sets the header
+func WithHeader(header string) Option {
+	return func(o *options) {
+		o.header = header
+	}
+}
+
+// WithTTL returns an Option that sets how long responses are replayed. Defaults
+// to 24 hours.
+//
+// Parameters:
+//   - ttl: Time responses are kept
+//
+// Returns:
+//   - Option: Function that sets the TTL
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithLockTimeout returns an Option that sets how long a key stays reserved by
+// a request that never completes, e.g. because the instance crashed. Set it
+// above the longest time the handler may take. Defaults to one minute.
+//
+// Parameters:
+//   - timeout: Time after which an unfinished reservation expires
+//
+// Returns:
+//   - Option: Function that sets the lock timeout
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.lockTimeout = timeout
+	}
+}
+
+// WithMaxBodySize returns an Option that sets the largest request body read to
+// hash the request. Larger requests with a key abort with 413. Defaults to 1 MiB.
+//
+// Parameters:
+//   - size: Maximum request body size in bytes
+//
+// Returns:
+//   - Option: Function that sets the maximum body size
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+// WithRequired returns an Option that rejects requests without a key with 400.
+// By default they are handled without idempotency.
+//
+// Returns:
+//   - Option: Function that makes the key required
+func WithRequired() Option {
+	return func(o *options) {
+		o.required = true
+	}
+}
+
+// New creates a middleware making POST, PATCH and PUT requests idempotent. The
+// first request with an Idempotency-Key runs the handler and its response is
+// stored under the key and the user ID of the requester; retries with the same
+// key and body replay it with an Idempotent-Replayed header. Retries while the
+// first request runs abort with 409, and reusing a key for a different method,
+// URI or body aborts with 422. 5xx responses and errors left to
+// middlewares.ErrorHandler are not stored, so they can be retried. Add it to
+// the Middlewares of a route.Route or route.GroupRoute, after authentication.
+//
+// Parameters:
+//   - store: Store keeping the keys, e.g. NewMemoryStore() or NewPostgresStore(db)
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function handling idempotency keys
+//
+// Example:
+//
+//	store := idempotency.NewPostgresStore(db)
+//	route.Route{
+//	    Path: "/orders", Method: method.POST, Handler: createOrder,
+//	    Middlewares: []func(*gin.Context){idempotency.New(store, idempotency.WithRequired())},
+//	}
+func New(store Store, opts ...Option) gin.HandlerFunc {
+	o := &options{
+		header:      HeaderIdempotencyKey,
+		ttl:         defaultTTL,
+		lockTimeout: defaultLockTimeout,
+		maxBodySize: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodPost, http.MethodPatch, http.MethodPut:
+		default:
+			ctx.Next()
+			return
+		}
+
+		idempotencyKey := ctx.GetHeader(o.header)
+		if idempotencyKey == "" {
+			if o.required {
+				resp.AbortWithError(ctx, resp.ErrInvalidRequest(fmt.Errorf("missing %s header", o.header)))
+				return
+			}
+			ctx.Next()
+			return
+		}
+		if len(idempotencyKey) > maxKeyLength {
+			resp.AbortWithError(ctx, resp.ErrInvalidRequest(fmt.Errorf("%s header exceeds %d characters", o.header, maxKeyLength)))
+			return
+		}
+
+		requestHash, err := hashRequest(ctx, o.maxBodySize)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				resp.AbortWithError(ctx, resp.ErrRequestEntityTooLarge(err))
+				return
+			}
+			resp.AbortWithError(ctx, resp.ErrInvalidRequest(err))
+			return
+		}
+
+		c := ctx.Request.Context()
+		log := logger.FromContext(c).With(zap.String("prefix", "Idempotency")).Sugar()
+		key := scopedKey(ctx, idempotencyKey)
+
+		token, err := utils.RandString(tokenLength)
+		if err != nil {
+			resp.AbortWithError(ctx, resp.ErrInternalServer(fmt.Errorf("failed to generate reservation token: %w", err)))
+			return
+		}
+		record, err := store.Begin(c, key, token, requestHash, o.lockTimeout)
+		if err != nil {
+			log.Errorf("idempotency store failed: %v", err)
+			resp.AbortWithError(ctx, resp.ErrInternalServer(fmt.Errorf("idempotency store failed: %w", err)))
+			return
+		}
+		if record != nil {
+			switch {
+			case record.RequestHash != requestHash:
+				resp.AbortWithError(ctx, resp.ErrUnprocessableEntity(fmt.Errorf("%s was used for a different request", o.header)))
+			case !record.Completed:
+				resp.AbortWithError(ctx, resp.ErrConflict(fmt.Errorf("a request with the same %s is in progress", o.header)))
+			default:
+				replay(ctx, record.Response)
+			}
+			return
+		}
+
+		// The response is stored even if the client went away
+		storeCtx := context.WithoutCancel(c)
+		completed := false
+		defer func() {
+			// Release the key if the handler panicked or failed, so the request can be retried
+			if !completed {
+				if err := store.Release(storeCtx, key, token); err != nil {
+					log.Errorf("failed to release idempotency key: %v", err)
+				}
+			}
+		}()
+
+		before := ctx.Writer.Header().Clone()
+		writer := &capturingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+
+		// Errors recorded with ctx.Error are written by middlewares.ErrorHandler
+		// once this returns, so the response is not known yet
+		if len(ctx.Errors) > 0 && !ctx.Writer.Written() {
+			return
+		}
+		status := ctx.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		response := Response{StatusCode: status, Header: handlerHeaders(before, ctx.Writer.Header()), Body: writer.body.Bytes()}
+		if err := store.Complete(storeCtx, key, token, response, o.ttl); err != nil {
+			log.Errorf("failed to store idempotent response: %v", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// hashRequest returns the hash of the method, URI and body of the request and
+// puts the body back for the handler. Bodies larger than maxBodySize fail with
+// an *http.MaxBytesError.
+func hashRequest(ctx *gin.Context, maxBodySize int64) (string, error) {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s %s\n", ctx.Request.Method, ctx.Request.URL.RequestURI())
+
+	if ctx.Request.Body != nil && ctx.Request.Body != http.NoBody {
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodySize))
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		_ = ctx.Request.Body.Close()
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// scopedKey returns the store key of an idempotency key sent by the requester,
+// so clients cannot replay the responses of other users.
+func scopedKey(ctx *gin.Context, idempotencyKey string) string {
+	var userId any
+	if r := requesterFrom(ctx); r != nil {
+		userId = r.GetUserId()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v\n%s", userId, idempotencyKey)))
+	return hex.EncodeToString(sum[:])
+}
+
+// handlerHeaders returns the response headers set or changed by the handler.
+func handlerHeaders(before, after http.Header) http.Header {
+	headers := http.Header{}
+	for name, values := range after {
+		if !slices.Equal(before[name], values) && !slices.Contains(unstoredHeaders, name) {
+			headers[name] = slices.Clone(values)
+		}
+	}
+	return headers
+}
+
+// replay writes a stored response.
+func replay(ctx *gin.Context, response Response) {
+	for name, values := range response.Header {
+		ctx.Writer.Header()[name] = values
+	}
+	ctx.Header(HeaderReplayed, "true")
+	ctx.Status(response.StatusCode)
+	_, _ = ctx.Writer.Write(response.Body)
+	ctx.Abort()
+}
+
+// requesterFrom returns the requester stored in the gin or request context.
+func requesterFrom(ctx *gin.Context) requester.CtxRequester {
+	if value, ok := ctx.Get(string(ctxkey.CtxRequesterKey)); ok {
+		if r, ok := value.(requester.CtxRequester); ok {
+			return r
+		}
+	}
+	if r, err := metadata.GetRequester(ctx.Request.Context()); err == nil {
+		return r
+	}
+	return nil
+}
+
+// capturingWriter keeps a copy of the response body.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
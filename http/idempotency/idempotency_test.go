@@ -0,0 +1,190 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/middlewares"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct{ MemoryStore }
+
+func (*failingStore) Begin(context.Context, string, string, string, time.Duration) (*Record, error) {
+	return nil, errors.New("database down")
+}
+
+func newTestEngine(middleware gin.HandlerFunc, calls *atomic.Int64, block chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		if userId := ctx.GetHeader("X-User"); userId != "" {
+			ctx.Set(string(ctxkey.CtxRequesterKey), requester.NewCtxRequester(userId))
+		}
+	}, middleware)
+	engine.POST("/orders", func(ctx *gin.Context) {
+		n := calls.Add(1)
+		if block != nil {
+			<-block
+		}
+		var body map[string]any
+		_ = ctx.ShouldBindJSON(&body)
+		ctx.Header("Location", fmt.Sprintf("/orders/%d", n))
+		ctx.JSON(http.StatusCreated, gin.H{"id": n, "item": body["item"]})
+	})
+	engine.PUT("/fail", func(ctx *gin.Context) {
+		calls.Add(1)
+		ctx.Status(http.StatusBadGateway)
+	})
+	engine.GET("/orders", func(ctx *gin.Context) {
+		calls.Add(1)
+		ctx.Status(http.StatusOK)
+	})
+	return engine
+}
+
+func serve(engine *gin.Engine, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestNew(t *testing.T) {
+	key := map[string]string{HeaderIdempotencyKey: "order-1"}
+
+	t.Run("replays the first response", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(NewMemoryStore()), &calls, nil)
+
+		first := serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, key)
+		retry := serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, key)
+		other := serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, map[string]string{HeaderIdempotencyKey: "order-2"})
+
+		assert.Equal(t, int64(2), calls.Load())
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "/orders/1", retry.Header().Get("Location"))
+		assert.Equal(t, "true", retry.Header().Get(HeaderReplayed))
+		assert.Empty(t, first.Header().Get(HeaderReplayed))
+		assert.JSONEq(t, `{"id":2,"item":"book"}`, other.Body.String())
+	})
+
+	t.Run("rejects key reuse with a different body", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(NewMemoryStore()), &calls, nil)
+
+		serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, key)
+		recorder := serve(engine, http.MethodPost, "/orders", `{"item":"pen"}`, key)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("rejects concurrent duplicates", func(t *testing.T) {
+		var calls atomic.Int64
+		block := make(chan struct{})
+		engine := newTestEngine(New(NewMemoryStore()), &calls, block)
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, key) }()
+		require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+		recorder := serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, key)
+		close(block)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("keys are scoped to the requester", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(NewMemoryStore()), &calls, nil)
+
+		serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, map[string]string{HeaderIdempotencyKey: "order-1", "X-User": "alice"})
+		recorder := serve(engine, http.MethodPost, "/orders", `{"item":"book"}`, map[string]string{HeaderIdempotencyKey: "order-1", "X-User": "bob"})
+
+		assert.Empty(t, recorder.Header().Get(HeaderReplayed))
+		assert.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(NewMemoryStore()), &calls, nil)
+
+		serve(engine, http.MethodPut, "/fail", `{}`, key)
+		recorder := serve(engine, http.MethodPut, "/fail", `{}`, key)
+
+		assert.Equal(t, http.StatusBadGateway, recorder.Code)
+		assert.Equal(t, int64(2), calls.Load())
+	})
+
+	t.Run("requests without a key", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(NewMemoryStore(), WithRequired()), &calls, nil)
+
+		assert.Equal(t, http.StatusBadRequest, serve(engine, http.MethodPost, "/orders", `{}`, nil).Code)
+		assert.Equal(t, http.StatusOK, serve(engine, http.MethodGet, "/orders", "", nil).Code)
+		assert.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(&failingStore{}), &calls, nil)
+
+		recorder := serve(engine, http.MethodPost, "/orders", `{}`, key)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(t, int64(0), calls.Load())
+	})
+
+	t.Run("rejects bodies above the maximum size", func(t *testing.T) {
+		var calls atomic.Int64
+		engine := newTestEngine(New(NewMemoryStore(), WithMaxBodySize(16)), &calls, nil)
+
+		recorder := serve(engine, http.MethodPost, "/orders", `{"item":"a long book title"}`, key)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+		assert.Equal(t, int64(0), calls.Load())
+	})
+}
+
+func TestNewWithErrorHandler(t *testing.T) {
+	var calls atomic.Int64
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middlewares.ErrorHandler(), New(NewMemoryStore()))
+	engine.POST("/orders", func(ctx *gin.Context) {
+		if calls.Add(1) == 1 {
+			_ = ctx.Error(errors.New("payment provider unavailable"))
+			return
+		}
+		ctx.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	headers := map[string]string{HeaderIdempotencyKey: "key-1"}
+	first := serve(engine, http.MethodPost, "/orders", `{}`, headers)
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+
+	retry := serve(engine, http.MethodPost, "/orders", `{}`, headers)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.JSONEq(t, `{"id":1}`, retry.Body.String())
+	assert.Empty(t, retry.Header().Get(HeaderReplayed))
+	assert.Equal(t, int64(2), calls.Load())
+}
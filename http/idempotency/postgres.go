@@ -0,0 +1,208 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anthanhphan/saturday/db/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// beginAttempts bounds the retries of Begin when a key is released while it is read.
+const beginAttempts = 3
+
+var _ Store = (*PostgresStore)(nil)
+
+// idempotencyKey is the row of an idempotency key.
+type idempotencyKey struct {
+	Key         string `gorm:"primaryKey;size:64"`
+	Token       string `gorm:"size:64;not null;default:''"`
+	RequestHash string `gorm:"size:64;not null"`
+	Completed   bool   `gorm:"not null;default:false"`
+	StatusCode  int
+	Header      string `gorm:"type:text"`
+	Body        []byte
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time
+}
+
+// TableName returns the table of idempotency keys.
+func (idempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// PostgresStore is a Store keeping keys in the idempotency_keys table, shared
+// between every instance of the service. Create the table with Migrate.
+type PostgresStore struct {
+	db  *postgres.Database
+	now func() time.Time
+}
+
+// NewPostgresStore creates a PostgresStore using db.
+//
+// Parameters:
+//   - db: The database
+//
+// Returns:
+//   - *PostgresStore: The store
+//
+// Example:
+//
+//	store := idempotency.NewPostgresStore(db)
+//	if err := store.Migrate(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewPostgresStore(db *postgres.Database) *PostgresStore {
+	return &PostgresStore{db: db, now: time.Now}
+}
+
+// Migrate creates or updates the idempotency_keys table.
+//
+// Parameters:
+//   - ctx: Context of the migration
+//
+// Returns:
+//   - error: Error if the migration fails
+func (s *PostgresStore) Migrate(ctx context.Context) error {
+	if err := s.db.Executor.WithContext(ctx).AutoMigrate(&idempotencyKey{}); err != nil {
+		return fmt.Errorf("failed to migrate idempotency keys: %w", err)
+	}
+	return nil
+}
+
+// Begin reserves key with token for a request for lockTTL. Expired keys are
+// taken over in the same statement.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Idempotency key
+//   - token: Token of the reservation
+//   - requestHash: Hash of the request
+//   - lockTTL: Time after which an unfinished reservation expires
+//
+// Returns:
+//   - *Record: Existing record of the key, or nil once reserved
+//   - error: Error if the database fails
+func (s *PostgresStore) Begin(ctx context.Context, key, token, requestHash string, lockTTL time.Duration) (*Record, error) {
+	db := s.db.Executor.WithContext(ctx)
+
+	for range beginAttempts {
+		now := s.now()
+		row := idempotencyKey{Key: key, Token: token, RequestHash: requestHash, ExpiresAt: now.Add(lockTTL), CreatedAt: now}
+		result := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"token", "request_hash", "completed", "status_code", "header", "body", "expires_at", "created_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Lt{Column: clause.Column{Table: row.TableName(), Name: "expires_at"}, Value: now}}},
+		}).Create(&row)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return nil, nil
+		}
+
+		var existing idempotencyKey
+		err := db.Where("key = ?", key).Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released in the meantime, try to reserve it again
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+		return existing.record()
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key after %d attempts", beginAttempts)
+}
+
+// Complete stores the response of the reservation of key made with token for ttl.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Idempotency key
+//   - token: Token of the reservation
+//   - response: Response to replay
+//   - ttl: Time the response is kept
+//
+// Returns:
+//   - error: Error if the database fails
+func (s *PostgresStore) Complete(ctx context.Context, key, token string, response Response, ttl time.Duration) error {
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return fmt.Errorf("failed to encode response header: %w", err)
+	}
+
+	err = s.db.Executor.WithContext(ctx).Model(&idempotencyKey{}).
+		Where("key = ? AND token = ? AND completed = ?", key, token, false).
+		Updates(map[string]any{
+			"completed":   true,
+			"status_code": response.StatusCode,
+			"header":      string(header),
+			"body":        response.Body,
+			"expires_at":  s.now().Add(ttl),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release drops the reservation of key made with token.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Idempotency key
+//   - token: Token of the reservation
+//
+// Returns:
+//   - error: Error if the database fails
+func (s *PostgresStore) Release(ctx context.Context, key, token string) error {
+	err := s.db.Executor.WithContext(ctx).
+		Where("key = ? AND token = ? AND completed = ?", key, token, false).
+		Delete(&idempotencyKey{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes expired keys. Expired keys are reused by Begin, so this
+// only reclaims space; run it periodically, e.g. with the routine package.
+//
+// Parameters:
+//   - ctx: Context of the deletion
+//
+// Returns:
+//   - int64: Number of deleted keys
+//   - error: Error if the database fails
+func (s *PostgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result := s.db.Executor.WithContext(ctx).Where("expires_at < ?", s.now()).Delete(&idempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// record converts the row into a Record.
+func (k idempotencyKey) record() (*Record, error) {
+	record := &Record{RequestHash: k.RequestHash, Completed: k.Completed}
+	if !k.Completed {
+		return record, nil
+	}
+
+	record.Response = Response{StatusCode: k.StatusCode, Body: k.Body}
+	if k.Header != "" {
+		if err := json.Unmarshal([]byte(k.Header), &record.Response.Header); err != nil {
+			return nil, fmt.Errorf("failed to decode response header: %w", err)
+		}
+	}
+	if record.Response.Header == nil {
+		record.Response.Header = http.Header{}
+	}
+	return record, nil
+}
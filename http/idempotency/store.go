@@ -0,0 +1,167 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops expired keys.
+const sweepInterval = time.Minute
+
+// Response is a stored response replayed for retried requests.
+//
+// Fields:
+//   - StatusCode: HTTP status code
+//   - Header: Headers set by the handler
+//   - Body: Response body
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Record is the state of an idempotency key.
+//
+// Fields:
+//   - RequestHash: Hash of the method, URI and body of the first request
+//   - Completed: Whether the first request finished; false while it is in progress
+//   - Response: Response of the first request, set once completed
+type Record struct {
+	RequestHash string
+	Completed   bool
+	Response    Response
+}
+
+// Store keeps idempotency keys and their responses. Implementations must be
+// safe for concurrent use and reserve keys atomically, so only one of several
+// concurrent requests with the same key runs the handler. Reservations carry a
+// token unique to the request, so a request whose reservation expired and was
+// taken over cannot complete or release the newer one.
+type Store interface {
+	// Begin reserves key with token for a request for lockTTL. It returns the
+	// record of the key if it is already reserved or completed, and nil once it
+	// is reserved.
+	Begin(ctx context.Context, key, token, requestHash string, lockTTL time.Duration) (*Record, error)
+	// Complete stores the response of the reservation of key made with token for ttl.
+	Complete(ctx context.Context, key, token string, response Response, ttl time.Duration) error
+	// Release drops the reservation of key made with token so the request can be retried.
+	Release(ctx context.Context, key, token string) error
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore is a Store keeping keys in process memory. Keys are not shared
+// between instances and are lost on restart; use PostgresStore when the
+// service runs more than one instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*memoryRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryRecord struct {
+	Record
+	token     string
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+//
+// Returns:
+//   - *MemoryStore: The store
+//
+// Example:
+//
+//	middleware := idempotency.New(idempotency.NewMemoryStore())
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]*memoryRecord{},
+		now:     time.Now,
+	}
+}
+
+// Begin reserves key with token for a request for lockTTL.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Idempotency key
+//   - token: Token of the reservation
+//   - requestHash: Hash of the request
+//   - lockTTL: Time after which an unfinished reservation expires
+//
+// Returns:
+//   - *Record: Existing record of the key, or nil once reserved
+//   - error: Always nil
+func (s *MemoryStore) Begin(_ context.Context, key, token, requestHash string, lockTTL time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if r, ok := s.records[key]; ok && now.Before(r.expiresAt) {
+		record := r.Record
+		return &record, nil
+	}
+	s.records[key] = &memoryRecord{Record: Record{RequestHash: requestHash}, token: token, expiresAt: now.Add(lockTTL)}
+	return nil, nil
+}
+
+// Complete stores the response of the reservation of key made with token for ttl.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Idempotency key
+//   - token: Token of the reservation
+//   - response: Response to replay
+//   - ttl: Time the response is kept
+//
+// Returns:
+//   - error: Always nil
+func (s *MemoryStore) Complete(_ context.Context, key, token string, response Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok && !r.Completed && r.token == token {
+		r.Completed = true
+		r.Response = response
+		r.expiresAt = s.now().Add(ttl)
+	}
+	return nil
+}
+
+// Release drops the reservation of key made with token.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Idempotency key
+//   - token: Token of the reservation
+//
+// Returns:
+//   - error: Always nil
+func (s *MemoryStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok && !r.Completed && r.token == token {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// sweep drops expired keys, at most once per sweepInterval.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, r := range s.records {
+		if !now.Before(r.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	record, err := store.Begin(ctx, "key", "first", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = store.Begin(ctx, "key", "second", "other", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, &Record{RequestHash: "hash"}, record)
+
+	// An unfinished reservation expires after the lock timeout
+	now = now.Add(2 * time.Minute)
+	record, err = store.Begin(ctx, "key", "second", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// The expired reservation can neither complete nor release the new one
+	stale := Response{StatusCode: http.StatusOK, Body: []byte(`{"stale":true}`)}
+	require.NoError(t, store.Complete(ctx, "key", "first", stale, time.Hour))
+	require.NoError(t, store.Release(ctx, "key", "first"))
+	record, err = store.Begin(ctx, "key", "third", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, &Record{RequestHash: "hash"}, record)
+
+	response := Response{StatusCode: http.StatusCreated, Header: http.Header{"Location": {"/orders/1"}}, Body: []byte(`{}`)}
+	require.NoError(t, store.Complete(ctx, "key", "second", response, time.Hour))
+
+	// Completed keys are not released
+	require.NoError(t, store.Release(ctx, "key", "second"))
+	record, err = store.Begin(ctx, "key", "third", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, &Record{RequestHash: "hash", Completed: true, Response: response}, record)
+
+	now = now.Add(2 * time.Hour)
+	record, err = store.Begin(ctx, "key", "third", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	require.NoError(t, store.Release(ctx, "key", "third"))
+	record, err = store.Begin(ctx, "key", "fourth", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
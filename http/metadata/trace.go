@@ -0,0 +1,35 @@
+package metadata
+
+import (
+	"context"
+
+	"github.com/anthanhphan/saturday/reqctx"
+)
+
+// TraceParent is a parsed W3C Trace Context traceparent header, see reqctx.TraceParent.
+type TraceParent = reqctx.TraceParent
+
+// ParseTraceParent parses a traceparent header value, see reqctx.ParseTraceParent.
+func ParseTraceParent(value string) (TraceParent, error) {
+	return reqctx.ParseTraceParent(value)
+}
+
+// SetRequestId returns a copy of ctx carrying the request ID, see reqctx.SetRequestId.
+func SetRequestId(ctx context.Context, requestId string) context.Context {
+	return reqctx.SetRequestId(ctx, requestId)
+}
+
+// GetRequestId returns the request ID of ctx, or "" if there is none.
+func GetRequestId(ctx context.Context) string {
+	return reqctx.GetRequestId(ctx)
+}
+
+// SetTraceParent returns a copy of ctx carrying the trace parent, see reqctx.SetTraceParent.
+func SetTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return reqctx.SetTraceParent(ctx, tp)
+}
+
+// GetTraceParent returns the trace parent of ctx and whether there is one.
+func GetTraceParent(ctx context.Context) (TraceParent, bool) {
+	return reqctx.GetTraceParent(ctx)
+}
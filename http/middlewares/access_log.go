@@ -0,0 +1,289 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redactedValue = "******"
+
+// defaultRedactKeys are JSON keys whose values are never written to the access log.
+var defaultRedactKeys = []string{"password", "token", "access_token", "refresh_token", "secret", "authorization"}
+
+// AccessLogOption is a function type that modifies the AccessLog middleware configuration.
+type AccessLogOption func(*accessLogOptions)
+
+type accessLogOptions struct {
+	sampleRate   float64
+	skipPaths    []string
+	captureBody  bool
+	maxBodyBytes int
+	redactKeys   map[string]bool
+}
+
+// AccessLogSampleRate returns an AccessLogOption that logs only a fraction of
+// successful requests. Requests answered with 4xx or 5xx are always logged.
+//
+// Parameters:
+//   - rate: Fraction of requests to log, between 0 and 1
+//
+// Returns:
+//   - AccessLogOption: Function that sets the sample rate
+func AccessLogSampleRate(rate float64) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.sampleRate = rate
+	}
+}
+
+// AccessLogSkipPaths returns an AccessLogOption that never logs the given paths.
+// A path matches the route template or the request path, and a trailing "*"
+// matches any suffix.
+//
+// Parameters:
+//   - paths: Paths to skip, e.g. "/health-check"
+//
+// Returns:
+//   - AccessLogOption: Function that adds the skipped paths
+func AccessLogSkipPaths(paths ...string) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// AccessLogBody returns an AccessLogOption that captures JSON request and response
+// bodies. Values of the default sensitive keys (password, token, secret, ...) and
+// of redactKeys are replaced by "******"; other bodies are logged by size only.
+//
+// Parameters:
+//   - maxBytes: Maximum captured size of each body; larger bodies are logged by size only
+//   - redactKeys: Additional JSON keys to redact, matched case-insensitively
+//
+// Returns:
+//   - AccessLogOption: Function that enables body capture
+func AccessLogBody(maxBytes int, redactKeys ...string) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.captureBody = true
+		o.maxBodyBytes = maxBytes
+		for _, key := range redactKeys {
+			o.redactKeys[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// AccessLog creates a middleware that writes one structured log line per request
+// with the method, route template, status, latency, bytes in/out, client IP, user
+// ID of the requester and request ID. 5xx responses are logged at error level,
+// 4xx at warn level and the rest at info level. Register it after Compress so
+// the response size and body are logged uncompressed.
+//
+// Parameters:
+//   - opts: Variable number of AccessLogOption functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function logging the request
+//
+// Examples:
+//
+//	router.Use(middlewares.RequestId(), middlewares.AccessLog(
+//	    middlewares.AccessLogSkipPaths("/health-check"),
+//	    middlewares.AccessLogSampleRate(0.1),
+//	    middlewares.AccessLogBody(4096, "card_number"),
+//	))
+func AccessLog(opts ...AccessLogOption) gin.HandlerFunc {
+	o := &accessLogOptions{
+		sampleRate: 1,
+		redactKeys: map[string]bool{},
+	}
+	for _, key := range defaultRedactKeys {
+		o.redactKeys[key] = true
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(ctx *gin.Context) {
+		if matchPath(o.skipPaths, ctx) {
+			ctx.Next()
+			return
+		}
+
+		start := time.Now()
+
+		body := &countingReader{ReadCloser: ctx.Request.Body}
+		if ctx.Request.Body != nil {
+			ctx.Request.Body = body
+		}
+
+		var requestBody []byte
+		if o.captureBody && ctx.Request.Body != nil {
+			requestBody = peekBody(ctx.Request, o.maxBodyBytes)
+		}
+
+		var writer *capturingWriter
+		if o.captureBody {
+			writer = &capturingWriter{ResponseWriter: ctx.Writer, max: o.maxBodyBytes}
+			ctx.Writer = writer
+		}
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		if status < http.StatusBadRequest && o.sampleRate < 1 && rand.Float64() >= o.sampleRate {
+			return
+		}
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = ctx.Request.URL.Path
+		}
+
+		fields := []zap.Field{
+			zap.String("method", ctx.Request.Method),
+			zap.String("route", route),
+			zap.String("path", ctx.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int64("bytes_in", max(ctx.Request.ContentLength, body.n)),
+			zap.Int("bytes_out", max(ctx.Writer.Size(), 0)),
+			zap.String("client_ip", ctx.ClientIP()),
+			zap.String("user_agent", ctx.Request.UserAgent()),
+		}
+		if r := requesterOf(ctx); r != nil {
+			fields = append(fields, zap.Any("user_id", r.GetUserId()))
+		}
+		if o.captureBody {
+			fields = append(fields,
+				zap.String("request_body", o.redactBody(requestBody, o.maxBodyBytes)),
+				zap.String("response_body", o.redactBody(writer.body.Bytes(), o.maxBodyBytes)),
+			)
+		}
+		if len(ctx.Errors) > 0 {
+			fields = append(fields, zap.String("errors", ctx.Errors.String()))
+		}
+
+		level := zapcore.InfoLevel
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = zapcore.ErrorLevel
+		case status >= http.StatusBadRequest:
+			level = zapcore.WarnLevel
+		}
+
+		log := logger.FromContext(ctx.Request.Context()).WithOptions(zap.WithCaller(false))
+		if entry := log.Check(level, "access log"); entry != nil {
+			entry.Write(fields...)
+		}
+	}
+}
+
+// requesterOf returns the requester stored by the Auth middleware, if any.
+func requesterOf(ctx *gin.Context) requester.CtxRequester {
+	if value, ok := ctx.Get(string(ctxkey.CtxRequesterKey)); ok {
+		if r, ok := value.(requester.CtxRequester); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// peekBody reads up to limit+1 bytes of the request body and puts them back so
+// handlers still read the full body.
+func peekBody(req *http.Request, limit int) []byte {
+	peeked, _ := io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peeked), req.Body), Closer: req.Body}
+	return peeked
+}
+
+// redactBody returns a JSON body with sensitive values redacted. Bodies that are
+// not JSON or exceed limit are described by their size only.
+func (o *accessLogOptions) redactBody(body []byte, limit int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if len(body) > limit {
+		return fmt.Sprintf("<%d+ bytes>", limit)
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+
+	redacted, err := json.Marshal(o.redact(document))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	return string(redacted)
+}
+
+// redact replaces the values of sensitive keys in a decoded JSON document.
+func (o *accessLogOptions) redact(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if o.redactKeys[strings.ToLower(key)] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = o.redact(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = o.redact(value)
+		}
+	}
+	return node
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// capturingWriter keeps the first max+1 bytes of the response body.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+	max  int
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *capturingWriter) capture(data []byte) {
+	if remaining := w.max + 1 - w.body.Len(); remaining > 0 {
+		w.body.Write(data[:min(len(data), remaining)])
+	}
+}
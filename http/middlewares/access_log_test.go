@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newAccessLogEngine(opts ...AccessLogOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RequestId(), AccessLog(opts...))
+	engine.POST("/users/:id", func(ctx *gin.Context) {
+		logger.FromContext(ctx.Request.Context()).Info("handler")
+		var body map[string]any
+		_ = ctx.ShouldBindJSON(&body)
+		ctx.JSON(http.StatusOK, gin.H{"id": ctx.Param("id"), "token": "abc"})
+	})
+	engine.GET("/health-check", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	engine.GET("/fail", func(ctx *gin.Context) { ctx.Status(http.StatusInternalServerError) })
+	return engine
+}
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	undo := zap.ReplaceGlobals(zap.New(core))
+	t.Cleanup(undo)
+	return logs
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Run("fields and request id", func(t *testing.T) {
+		logs := observeLogs(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/users/7", strings.NewReader(`{"name":"a"}`))
+		newAccessLogEngine().ServeHTTP(httptest.NewRecorder(), req)
+
+		entries := logs.All()
+		require.Len(t, entries, 2)
+
+		handler, access := entries[0], entries[1]
+		requestId := handler.ContextMap()[logger.LogRequestIdKey]
+		assert.NotEmpty(t, requestId)
+
+		fields := access.ContextMap()
+		assert.Equal(t, zapcore.InfoLevel, access.Level)
+		assert.Equal(t, requestId, fields[logger.LogRequestIdKey])
+		assert.Equal(t, "POST", fields["method"])
+		assert.Equal(t, "/users/:id", fields["route"])
+		assert.Equal(t, "/users/7", fields["path"])
+		assert.EqualValues(t, http.StatusOK, fields["status"])
+		assert.EqualValues(t, 12, fields["bytes_in"])
+		assert.NotZero(t, fields["bytes_out"])
+		assert.NotContains(t, fields, "request_body")
+	})
+
+	t.Run("server errors logged at error level", func(t *testing.T) {
+		logs := observeLogs(t)
+
+		newAccessLogEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, zapcore.ErrorLevel, logs.All()[0].Level)
+	})
+
+	t.Run("skipped paths", func(t *testing.T) {
+		logs := observeLogs(t)
+
+		engine := newAccessLogEngine(AccessLogSkipPaths("/health-check"))
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health-check", nil))
+
+		assert.Zero(t, logs.Len())
+	})
+
+	t.Run("sampling keeps failures", func(t *testing.T) {
+		logs := observeLogs(t)
+
+		engine := newAccessLogEngine(AccessLogSampleRate(0))
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health-check", nil))
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+		require.Equal(t, 1, logs.Len())
+		assert.EqualValues(t, http.StatusInternalServerError, logs.All()[0].ContextMap()["status"])
+	})
+
+	t.Run("bodies are redacted", func(t *testing.T) {
+		logs := observeLogs(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/users/7", strings.NewReader(`{"password":"p","card":"4111","name":"a"}`))
+		recorder := httptest.NewRecorder()
+		newAccessLogEngine(AccessLogBody(1024, "Card")).ServeHTTP(recorder, req)
+
+		assert.Contains(t, recorder.Body.String(), `"token":"abc"`)
+
+		fields := logs.FilterMessage("access log").All()[0].ContextMap()
+		assert.JSONEq(t, `{"password":"******","card":"******","name":"a"}`, fields["request_body"].(string))
+		assert.JSONEq(t, `{"id":"7","token":"******"}`, fields["response_body"].(string))
+	})
+
+	t.Run("oversized bodies logged by size", func(t *testing.T) {
+		logs := observeLogs(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/users/7", strings.NewReader(`{"name":"a long name"}`))
+		newAccessLogEngine(AccessLogBody(4)).ServeHTTP(httptest.NewRecorder(), req)
+
+		fields := logs.FilterMessage("access log").All()[0].ContextMap()
+		assert.Equal(t, "<4+ bytes>", fields["request_body"])
+	})
+}
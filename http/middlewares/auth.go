@@ -0,0 +1,285 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+const bearerPrefix = "Bearer "
+
+// AuthOption is a function type that modifies the Auth middleware configuration.
+type AuthOption func(*authOptions)
+
+type tokenSource struct {
+	kind string // "header", "cookie" or "query"
+	name string
+}
+
+type authOptions struct {
+	sources   []tokenSource
+	optional  bool
+	skipPaths []string
+	mapper    func(payload *jwt.Payload) (requester.CtxRequester, error)
+}
+
+// AuthFromHeader returns an AuthOption that reads the token from a header.
+// A "Bearer " prefix is stripped, and required for the Authorization header.
+//
+// Parameters:
+//   - name: Header name
+//
+// Returns:
+//   - AuthOption: Function that adds the header as a token source
+func AuthFromHeader(name string) AuthOption {
+	return func(o *authOptions) {
+		o.sources = append(o.sources, tokenSource{kind: "header", name: name})
+	}
+}
+
+// AuthFromCookie returns an AuthOption that reads the token from a cookie.
+//
+// Parameters:
+//   - name: Cookie name
+//
+// Returns:
+//   - AuthOption: Function that adds the cookie as a token source
+func AuthFromCookie(name string) AuthOption {
+	return func(o *authOptions) {
+		o.sources = append(o.sources, tokenSource{kind: "cookie", name: name})
+	}
+}
+
+// AuthFromQuery returns an AuthOption that reads the token from a query parameter.
+//
+// Parameters:
+//   - name: Query parameter name
+//
+// Returns:
+//   - AuthOption: Function that adds the query parameter as a token source
+func AuthFromQuery(name string) AuthOption {
+	return func(o *authOptions) {
+		o.sources = append(o.sources, tokenSource{kind: "query", name: name})
+	}
+}
+
+// AuthOptional returns an AuthOption that lets requests without a token through
+// without a requester. Requests with an invalid token are still rejected.
+//
+// Returns:
+//   - AuthOption: Function that makes authentication optional
+func AuthOptional() AuthOption {
+	return func(o *authOptions) {
+		o.optional = true
+	}
+}
+
+// AuthSkipPaths returns an AuthOption that skips authentication for paths.
+// A path matches the route template (e.g. "/users/:id") or the request path,
+// and a trailing "*" matches any suffix.
+//
+// Parameters:
+//   - paths: Paths to skip
+//
+// Returns:
+//   - AuthOption: Function that adds the skipped paths
+func AuthSkipPaths(paths ...string) AuthOption {
+	return func(o *authOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// AuthRequesterMapper returns an AuthOption that builds the requester from the
+// token payload, e.g. to read roles from custom claims.
+//
+// Parameters:
+//   - mapper: Function converting the payload; an error rejects the request with 401
+//
+// Returns:
+//   - AuthOption: Function that sets the mapper
+func AuthRequesterMapper(mapper func(payload *jwt.Payload) (requester.CtxRequester, error)) AuthOption {
+	return func(o *authOptions) {
+		o.mapper = mapper
+	}
+}
+
+// Auth creates a middleware that authenticates requests with a JWT. The token is
+// read from the Authorization Bearer header by default, validated, and the
+// resulting requester is stored in the gin context and the request context under
+// ctxkey.CtxRequesterKey. Failures abort with a 401 resp.ErrorResp.
+//
+// By default the requester gets its roles from the "roles" claim, its scopes from
+// the "scope" (space separated) or "scopes" claim and its tenant from "tenant_id".
+//
+// Parameters:
+//   - j: JWT service validating the tokens
+//   - opts: Variable number of AuthOption functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function authenticating the request
+//
+// Examples:
+//
+//	router.Use(middlewares.Auth(jwtService,
+//	    middlewares.AuthFromHeader("Authorization"),
+//	    middlewares.AuthFromCookie("access_token"),
+//	    middlewares.AuthSkipPaths("/health-check", "/public/*"),
+//	))
+//	// In handlers: r, err := metadata.GetRequester(ctx.Request.Context())
+func Auth(j jwt.Jwt, opts ...AuthOption) gin.HandlerFunc {
+	o := &authOptions{
+		mapper: claimsRequester,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.sources) == 0 {
+		o.sources = []tokenSource{{kind: "header", name: "Authorization"}}
+	}
+
+	return func(ctx *gin.Context) {
+		if o.skip(ctx) {
+			ctx.Next()
+			return
+		}
+
+		token, err := o.token(ctx)
+		if err != nil {
+			resp.AbortWithError(ctx, resp.ErrInvalidTokenFormat(err))
+			return
+		}
+		if token == "" {
+			if o.optional {
+				ctx.Next()
+				return
+			}
+			resp.AbortWithError(ctx, resp.ErrMissingTokenInHeader(errors.New("missing token")))
+			return
+		}
+
+		payload, err := j.Validate(token)
+		if err != nil {
+			resp.AbortWithError(ctx, resp.ErrInvalidTokenSignature(err))
+			return
+		}
+
+		r, err := o.mapper(payload)
+		if err != nil {
+			resp.AbortWithError(ctx, resp.NewErrorResp(http.StatusUnauthorized, err, "invalid token claims"))
+			return
+		}
+
+		ctx.Set(string(ctxkey.CtxRequesterKey), r)
+		ctx.Request = ctx.Request.WithContext(metadata.SetRequesterContextHeader(ctx))
+		ctx.Next()
+	}
+}
+
+// claimsRequester builds a requester from the user ID and the standard claims.
+func claimsRequester(payload *jwt.Payload) (requester.CtxRequester, error) {
+	options := []requester.Option{
+		requester.SetRoles(claimStrings(payload.Claims["roles"])...),
+		requester.SetScopes(claimStrings(payload.Claims["scopes"])...),
+	}
+	if scope, ok := payload.Claims["scope"].(string); ok {
+		options = append(options, requester.SetScopes(strings.Fields(scope)...))
+	}
+	if tenantId, ok := payload.Claims["tenant_id"]; ok {
+		options = append(options, requester.SetTenantId(tenantId))
+	}
+	return requester.NewCtxRequester(payload.UserId, options...), nil
+}
+
+// claimStrings converts a string or array claim to a string slice.
+func claimStrings(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// token returns the token from the first source that has one.
+func (o *authOptions) token(ctx *gin.Context) (string, error) {
+	for _, source := range o.sources {
+		var value string
+		switch source.kind {
+		case "header":
+			value = ctx.GetHeader(source.name)
+			if value == "" {
+				continue
+			}
+			if len(value) >= len(bearerPrefix) && strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+				value = value[len(bearerPrefix):]
+			} else if strings.EqualFold(source.name, "Authorization") {
+				return "", errors.New("authorization header must use the Bearer scheme")
+			}
+		case "cookie":
+			value, _ = ctx.Cookie(source.name)
+		case "query":
+			value = ctx.Query(source.name)
+		}
+
+		if value = strings.TrimSpace(value); value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// skip reports whether the request path is in the skip list.
+func (o *authOptions) skip(ctx *gin.Context) bool {
+	return matchPath(o.skipPaths, ctx)
+}
+
+// matchPath reports whether the route template or the cleaned request path of
+// ctx matches one of patterns. A trailing "*" in a pattern matches any suffix.
+// The raw path is never matched: "/public/../admin" would otherwise match
+// "/public/*" while the router serves "/admin".
+func matchPath(patterns []string, ctx *gin.Context) bool {
+	fullPath, reqPath := ctx.FullPath(), cleanPath(ctx.Request.URL.Path)
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(reqPath, prefix) || (fullPath != "" && strings.HasPrefix(fullPath, prefix)) {
+				return true
+			}
+			continue
+		}
+		if pattern == reqPath || pattern == fullPath {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanPath resolves "." and ".." elements and repeated slashes of the
+// unescaped request path, keeping a trailing slash.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
@@ -0,0 +1,171 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/jwt"
+	"github.com/anthanhphan/saturday/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJwt(t *testing.T) (jwt.Jwt, string) {
+	t.Helper()
+
+	keys, err := utils.GenerateRsaKeyPair()
+	require.NoError(t, err)
+
+	j := jwt.NewJwt(keys.PrivateKey, keys.PublicKey)
+	token, err := j.Generate(&jwt.Payload{UserId: 42}, 3600)
+	require.NoError(t, err)
+	return j, *token
+}
+
+func newAuthEngine(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(handler)
+	engine.GET("/users/:id", func(ctx *gin.Context) {
+		r, err := metadata.GetRequester(ctx.Request.Context())
+		if err != nil {
+			ctx.String(http.StatusOK, "anonymous")
+			return
+		}
+		ctx.JSON(http.StatusOK, r.GetUserId())
+	})
+	return engine
+}
+
+func TestAuth(t *testing.T) {
+	j, token := newTestJwt(t)
+
+	tests := []struct {
+		name       string
+		opts       []AuthOption
+		setup      func(req *http.Request)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "bearer header",
+			setup:      func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+			wantStatus: http.StatusOK,
+			wantBody:   "42",
+		},
+		{
+			name:       "missing token",
+			setup:      func(*http.Request) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong scheme",
+			setup:      func(req *http.Request) { req.Header.Set("Authorization", "Basic abc") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			setup:      func(req *http.Request) { req.Header.Set("Authorization", "Bearer invalid") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "cookie",
+			opts: []AuthOption{AuthFromHeader("Authorization"), AuthFromCookie("access_token")},
+			setup: func(req *http.Request) {
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "42",
+		},
+		{
+			name:       "query",
+			opts:       []AuthOption{AuthFromQuery("token")},
+			setup:      func(req *http.Request) { req.URL.RawQuery = "token=" + token },
+			wantStatus: http.StatusOK,
+			wantBody:   "42",
+		},
+		{
+			name:       "optional without token",
+			opts:       []AuthOption{AuthOptional()},
+			setup:      func(*http.Request) {},
+			wantStatus: http.StatusOK,
+			wantBody:   "anonymous",
+		},
+		{
+			name:       "skipped route template",
+			opts:       []AuthOption{AuthSkipPaths("/users/:id")},
+			setup:      func(*http.Request) {},
+			wantStatus: http.StatusOK,
+			wantBody:   "anonymous",
+		},
+		{
+			name:       "skipped prefix",
+			opts:       []AuthOption{AuthSkipPaths("/users/*")},
+			setup:      func(*http.Request) {},
+			wantStatus: http.StatusOK,
+			wantBody:   "anonymous",
+		},
+		{
+			name: "custom mapper",
+			opts: []AuthOption{AuthRequesterMapper(func(payload *jwt.Payload) (requester.CtxRequester, error) {
+				return requester.NewCtxRequester(payload.UserId * 2), nil
+			})},
+			setup:      func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+			wantStatus: http.StatusOK,
+			wantBody:   "84",
+		},
+		{
+			name: "mapper error",
+			opts: []AuthOption{AuthRequesterMapper(func(*jwt.Payload) (requester.CtxRequester, error) {
+				return nil, errors.New("no roles")
+			})},
+			setup:      func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			tt.setup(req)
+
+			recorder := httptest.NewRecorder()
+			newAuthEngine(Auth(j, tt.opts...)).ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthSkipPathTraversal(t *testing.T) {
+	j, _ := newTestJwt(t)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.RemoveExtraSlash = true
+	engine.Use(Auth(j, AuthSkipPaths("/public/*")))
+	engine.GET("/public/docs", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	engine.GET("/admin", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	for path, wantStatus := range map[string]int{
+		"/public/docs":           http.StatusOK,
+		"/admin":                 http.StatusUnauthorized,
+		"/public/../admin":       http.StatusUnauthorized,
+		"/public/%2e%2e/admin":   http.StatusUnauthorized,
+		"/public//../../admin":   http.StatusUnauthorized,
+		"/public/./%2E%2E/admin": http.StatusUnauthorized,
+	} {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, wantStatus, recorder.Code, path)
+	}
+}
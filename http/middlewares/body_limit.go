@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit creates a middleware limiting request bodies to maxBytes. Requests
+// declaring a larger Content-Length abort with 413 before the handler runs.
+// Reading past the limit of other bodies fails with *http.MaxBytesError, which
+// route.Typed answers with 413; the middleware answers with 413 itself when the
+// handler wrote no response. Add it to the Middlewares of a route.Route or
+// route.GroupRoute; the smallest limit of nested middlewares applies.
+//
+// Parameters:
+//   - maxBytes: Maximum body size in bytes
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function limiting the body
+//
+// Example:
+//
+//	route.Route{
+//	    Path: "/uploads", Method: method.POST, Handler: upload,
+//	    Middlewares: []func(*gin.Context){middlewares.BodyLimit(10 << 20)},
+//	}
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.ContentLength > maxBytes {
+			resp.AbortWithError(ctx, resp.ErrRequestEntityTooLarge(
+				fmt.Errorf("request body of %d bytes exceeds the limit of %d bytes", ctx.Request.ContentLength, maxBytes)))
+			return
+		}
+		if ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+			ctx.Next()
+			return
+		}
+
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)}
+		ctx.Request.Body = body
+		ctx.Next()
+
+		if body.exceeded && !ctx.Writer.Written() {
+			resp.AbortWithError(ctx, resp.ErrRequestEntityTooLarge(fmt.Errorf("request body exceeds the limit of %d bytes", maxBytes)))
+		}
+	}
+}
+
+// limitedBody records whether a body was read past its limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	type createReq struct {
+		Name string `json:"name"`
+	}
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(BodyLimit(16))
+	engine.POST("/raw", func(ctx *gin.Context) {
+		// The handler ignores the read error and writes nothing
+		_, _ = io.ReadAll(ctx.Request.Body)
+	})
+	engine.POST("/typed", route.Typed(func(_ context.Context, req createReq) (string, error) {
+		return req.Name, nil
+	}))
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		chunked    bool
+		wantStatus int
+	}{
+		{name: "within limit", path: "/typed", body: `{"name":"a"}`, wantStatus: http.StatusOK},
+		{name: "content length over limit", path: "/typed", body: `{"name":"saturday"}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "chunked body over limit bound by typed", path: "/typed", body: `{"name":"saturday"}`, chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "chunked body over limit read by handler", path: "/raw", body: strings.Repeat("a", 32), chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				assert.JSONEq(t, `{"status_code":413,"code":"request_too_large","message":"request body too large"}`, recorder.Body.String())
+			}
+		})
+	}
+}
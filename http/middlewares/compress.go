@@ -0,0 +1,402 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Content encodings supported by the Compress middleware
+const (
+	EncodingBrotli  = "br"
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
+const defaultCompressMinSize = 1024
+
+// defaultCompressTypes are the content types compressed by default.
+var defaultCompressTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/*",
+}
+
+// CompressOption is a function type that modifies the Compress middleware configuration.
+type CompressOption func(*compressOptions)
+
+type compressOptions struct {
+	minSize      int
+	contentTypes []string
+	encodings    []string
+	levels       map[string]int
+	skipPaths    []string
+}
+
+// CompressMinSize returns a CompressOption that leaves responses smaller than
+// size uncompressed. Defaults to 1024 bytes.
+//
+// Parameters:
+//   - size: Minimum response size in bytes
+//
+// Returns:
+//   - CompressOption: Function that sets the minimum size
+func CompressMinSize(size int) CompressOption {
+	return func(o *compressOptions) {
+		o.minSize = size
+	}
+}
+
+// CompressContentTypes returns a CompressOption that sets the compressed content
+// types, replacing the defaults (JSON, JavaScript, XML, SVG and text/*). A
+// trailing "/*" matches every subtype.
+//
+// Parameters:
+//   - types: Content types, e.g. "application/json" or "text/*"
+//
+// Returns:
+//   - CompressOption: Function that sets the content types
+func CompressContentTypes(types ...string) CompressOption {
+	return func(o *compressOptions) {
+		o.contentTypes = types
+	}
+}
+
+// CompressEncodings returns a CompressOption that sets the offered encodings in
+// order of preference. Defaults to br, gzip and deflate.
+//
+// Parameters:
+//   - encodings: EncodingBrotli, EncodingGzip or EncodingDeflate
+//
+// Returns:
+//   - CompressOption: Function that sets the encodings
+func CompressEncodings(encodings ...string) CompressOption {
+	return func(o *compressOptions) {
+		o.encodings = encodings
+	}
+}
+
+// CompressLevel returns a CompressOption that sets the compression level of an
+// encoding: 0-11 for brotli and -2-9 for gzip and deflate. Invalid levels fall
+// back to the default level.
+//
+// Parameters:
+//   - encoding: EncodingBrotli, EncodingGzip or EncodingDeflate
+//   - level: Compression level
+//
+// Returns:
+//   - CompressOption: Function that sets the level
+func CompressLevel(encoding string, level int) CompressOption {
+	return func(o *compressOptions) {
+		o.levels[encoding] = level
+	}
+}
+
+// CompressSkipPaths returns a CompressOption that never compresses the given
+// paths. A path matches the route template or the request path, and a trailing
+// "*" matches any suffix.
+//
+// Parameters:
+//   - paths: Paths to skip, e.g. "/downloads/*"
+//
+// Returns:
+//   - CompressOption: Function that adds the skipped paths
+func CompressSkipPaths(paths ...string) CompressOption {
+	return func(o *compressOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// encoder is the interface shared by the gzip, zlib and brotli writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compress creates a middleware compressing responses with the encoding of the
+// Accept-Encoding header the server prefers. Only responses of the configured
+// content types reaching the minimum size are compressed; responses that already
+// have a Content-Encoding, and 1xx, 204, 206 and 304 responses, are sent as is.
+// Flushing, e.g. by gin.Context.Stream, compresses and sends what was written
+// so far, so streaming responses keep working.
+//
+// Parameters:
+//   - opts: Variable number of CompressOption functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function compressing the response
+//
+// Examples:
+//
+//	router.Use(middlewares.Compress(
+//	    middlewares.CompressMinSize(512),
+//	    middlewares.CompressLevel(middlewares.EncodingGzip, gzip.BestSpeed),
+//	))
+func Compress(opts ...CompressOption) gin.HandlerFunc {
+	o := &compressOptions{
+		minSize:      defaultCompressMinSize,
+		contentTypes: defaultCompressTypes,
+		encodings:    []string{EncodingBrotli, EncodingGzip, EncodingDeflate},
+		levels:       map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	pools := map[string]*sync.Pool{}
+	for _, encoding := range o.encodings {
+		if pool := newEncoderPool(encoding, o.levels); pool != nil {
+			pools[encoding] = pool
+		}
+	}
+	encodings := make([]string, 0, len(pools))
+	for _, encoding := range o.encodings {
+		if pools[encoding] != nil {
+			encodings = append(encodings, encoding)
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		if matchPath(o.skipPaths, ctx) || ctx.GetHeader("Upgrade") != "" {
+			ctx.Next()
+			return
+		}
+
+		// Caches must keep one copy per encoding, even of responses sent uncompressed
+		ctx.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(ctx.GetHeader("Accept-Encoding"), encodings)
+		if encoding == "" {
+			ctx.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: ctx.Writer, options: o, encoding: encoding, pool: pools[encoding]}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+		writer.close()
+	}
+}
+
+// newEncoderPool returns a pool of writers of encoding, or nil if it is unsupported.
+func newEncoderPool(encoding string, levels map[string]int) *sync.Pool {
+	level, hasLevel := levels[encoding]
+	switch encoding {
+	case EncodingBrotli:
+		if !hasLevel || level < brotli.BestSpeed || level > brotli.BestCompression {
+			level = brotli.DefaultCompression
+		}
+		return &sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, level) }}
+	case EncodingGzip:
+		if !hasLevel {
+			level = gzip.DefaultCompression
+		}
+		return &sync.Pool{New: func() any {
+			w, err := gzip.NewWriterLevel(io.Discard, level)
+			if err != nil {
+				return gzip.NewWriter(io.Discard)
+			}
+			return w
+		}}
+	case EncodingDeflate:
+		if !hasLevel {
+			level = zlib.DefaultCompression
+		}
+		return &sync.Pool{New: func() any {
+			w, err := zlib.NewWriterLevel(io.Discard, level)
+			if err != nil {
+				return zlib.NewWriter(io.Discard)
+			}
+			return w
+		}}
+	}
+	return nil
+}
+
+// negotiateEncoding returns the first of supported accepted by an Accept-Encoding
+// header with the highest quality, or "" if none is accepted.
+func negotiateEncoding(header string, supported []string) string {
+	if header == "" {
+		return ""
+	}
+
+	weights := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				weight = q
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range supported {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the response until it reaches the minimum size, then
+// decides whether to compress it and streams the rest through the encoder.
+type compressWriter struct {
+	gin.ResponseWriter
+	options  *compressOptions
+	encoding string
+	pool     *sync.Pool
+	buffer   []byte
+	decided  bool
+	encoder  encoder
+	written  bool
+	size     int
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.written = true
+	if w.decided {
+		n, err := w.write(data)
+		w.size += n
+		return n, err
+	}
+
+	w.buffer = append(w.buffer, data...)
+	w.size += len(data)
+	if len(w.buffer) < w.options.minSize {
+		return len(data), nil
+	}
+	w.decide()
+	return len(data), w.flushBuffer()
+}
+
+// Written reports whether the handler wrote a response, including buffered bytes.
+func (w *compressWriter) Written() bool {
+	return w.written || w.ResponseWriter.Written()
+}
+
+// Size returns the number of uncompressed body bytes the handler wrote, or -1
+// if it wrote nothing.
+func (w *compressWriter) Size() int {
+	if !w.Written() {
+		return -1
+	}
+	return w.size
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what was written so far, compressing it if the content type allows.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if err := w.flushBuffer(); err != nil {
+		return
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide sets up the encoder if the response should be compressed.
+func (w *compressWriter) decide() {
+	w.decided = true
+	if !w.compressible() {
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	// The compressed representation differs byte for byte
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	w.encoder = w.pool.Get().(encoder)
+	w.encoder.Reset(w.ResponseWriter)
+}
+
+// compressible reports whether the status and content type of the response allow compression.
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	status := w.Status()
+	if header.Get("Content-Encoding") != "" || status < http.StatusOK ||
+		status == http.StatusNoContent || status == http.StatusPartialContent || status == http.StatusNotModified {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		if len(w.buffer) == 0 {
+			return false
+		}
+		// Sniff now, the compressed body would defeat net/http's detection
+		contentType = http.DetectContentType(w.buffer)
+		header.Set("Content-Type", contentType)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range w.options.contentTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(mediaType, prefix) || allowed == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *compressWriter) write(data []byte) (int, error) {
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) flushBuffer() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	buffer := w.buffer
+	w.buffer = nil
+	_, err := w.write(buffer)
+	return err
+}
+
+// close sends responses smaller than the minimum size as is and finishes the compressed stream.
+func (w *compressWriter) close() {
+	w.decided = true
+	_ = w.flushBuffer()
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		w.pool.Put(w.encoder)
+		w.encoder = nil
+	}
+}
@@ -0,0 +1,157 @@
+package middlewares
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var largeBody = strings.Repeat(`{"name":"saturday"},`, 100)
+
+func newCompressEngine(middlewares ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middlewares...)
+	engine.GET("/large", func(ctx *gin.Context) { ctx.Data(http.StatusOK, "application/json; charset=utf-8", []byte(largeBody)) })
+	engine.GET("/small", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"ok": true}) })
+	engine.GET("/image", func(ctx *gin.Context) { ctx.Data(http.StatusOK, "image/png", []byte(largeBody)) })
+	engine.GET("/encoded", func(ctx *gin.Context) {
+		ctx.Header("Content-Encoding", "gzip")
+		ctx.Data(http.StatusOK, "application/json", []byte(largeBody))
+	})
+	engine.GET("/stream", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "text/plain")
+		for _, chunk := range []string{"first\n", "second\n"} {
+			ctx.Stream(func(w io.Writer) bool {
+				_, _ = w.Write([]byte(chunk))
+				return false
+			})
+		}
+	})
+	return engine
+}
+
+func decode(t *testing.T, encoding string, body io.Reader) string {
+	var reader io.Reader
+	var err error
+	switch encoding {
+	case EncodingGzip:
+		reader, err = gzip.NewReader(body)
+	case EncodingDeflate:
+		reader, err = zlib.NewReader(body)
+	case EncodingBrotli:
+		reader = brotli.NewReader(body)
+	default:
+		reader = body
+	}
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(decoded)
+}
+
+func TestCompress(t *testing.T) {
+	engine := newCompressEngine(Compress())
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{name: "prefers brotli", path: "/large", acceptEncoding: "gzip, deflate, br", wantEncoding: EncodingBrotli},
+		{name: "gzip", path: "/large", acceptEncoding: "gzip", wantEncoding: EncodingGzip},
+		{name: "deflate", path: "/large", acceptEncoding: "deflate", wantEncoding: EncodingDeflate},
+		{name: "quality values", path: "/large", acceptEncoding: "br;q=0.5, gzip;q=0.8", wantEncoding: EncodingGzip},
+		{name: "refused encoding", path: "/large", acceptEncoding: "br;q=0, *", wantEncoding: EncodingGzip},
+		{name: "identity only", path: "/large", acceptEncoding: "identity"},
+		{name: "no accept encoding", path: "/large"},
+		{name: "below minimum size", path: "/small", acceptEncoding: "gzip"},
+		{name: "content type not compressible", path: "/image", acceptEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.wantEncoding, recorder.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+
+			body := decode(t, tt.wantEncoding, recorder.Body)
+			switch tt.path {
+			case "/small":
+				assert.JSONEq(t, `{"ok":true}`, body)
+			default:
+				assert.Equal(t, largeBody, body)
+			}
+		})
+	}
+
+	t.Run("already encoded", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/encoded", nil)
+		req.Header.Set("Accept-Encoding", "br")
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+
+		assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, recorder.Body.String())
+	})
+}
+
+func TestCompressOptions(t *testing.T) {
+	engine := newCompressEngine(Compress(
+		CompressMinSize(1),
+		CompressContentTypes("application/json"),
+		CompressEncodings(EncodingGzip),
+		CompressLevel(EncodingGzip, gzip.BestSpeed),
+		CompressSkipPaths("/large"),
+	))
+
+	for path, wantEncoding := range map[string]string{"/small": EncodingGzip, "/large": ""} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "br, gzip")
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+
+		assert.Equal(t, wantEncoding, recorder.Header().Get("Content-Encoding"), path)
+		assert.NotEmpty(t, decode(t, wantEncoding, recorder.Body), path)
+	}
+}
+
+func TestCompressStream(t *testing.T) {
+	server := httptest.NewServer(newCompressEngine(Compress(CompressMinSize(1))))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, EncodingGzip, res.Header.Get("Content-Encoding"))
+	reader, err := gzip.NewReader(res.Body)
+	require.NoError(t, err)
+	lines := bufio.NewScanner(reader)
+	var got []string
+	for lines.Scan() {
+		got = append(got, lines.Text())
+	}
+	assert.Equal(t, []string{"first", "second"}, got)
+}
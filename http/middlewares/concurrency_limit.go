@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimitOption is a function type that modifies the ConcurrencyLimit middleware configuration.
+type ConcurrencyLimitOption func(*concurrencyLimitOptions)
+
+type concurrencyLimitOptions struct {
+	queueSize    int
+	queueTimeout time.Duration
+}
+
+// ConcurrencyLimitQueue returns a ConcurrencyLimitOption that lets requests wait
+// for a free slot instead of being shed at once. By default requests arriving
+// while every slot is taken are answered with 503 immediately.
+//
+// Parameters:
+//   - size: Maximum number of waiting requests; 0 or less waits without a cap
+//   - timeout: Maximum time a request waits for a slot
+//
+// Returns:
+//   - ConcurrencyLimitOption: Function that enables the queue
+func ConcurrencyLimitQueue(size int, timeout time.Duration) ConcurrencyLimitOption {
+	return func(o *concurrencyLimitOptions) {
+		o.queueSize = size
+		o.queueTimeout = timeout
+	}
+}
+
+// ConcurrencyLimit creates a middleware allowing at most maxInFlight requests
+// to run at once. Requests that find no free slot, or wait longer than the
+// queue timeout, abort with a 503 resp.ErrorResp and a Retry-After header. Add
+// it to the Middlewares of a route.Route to limit that route, or of a
+// route.GroupRoute to share the slots between every route of the group, so one
+// slow endpoint cannot take every worker of the server. It panics if
+// maxInFlight is not positive.
+//
+// Parameters:
+//   - maxInFlight: Maximum number of concurrent requests
+//   - opts: Variable number of ConcurrencyLimitOption functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function limiting concurrency
+//
+// Example:
+//
+//	route.Route{
+//	    Path: "/exports", Method: method.POST, Handler: export,
+//	    Middlewares: []func(*gin.Context){
+//	        middlewares.ConcurrencyLimit(4, middlewares.ConcurrencyLimitQueue(16, 2*time.Second)),
+//	    },
+//	}
+func ConcurrencyLimit(maxInFlight int, opts ...ConcurrencyLimitOption) gin.HandlerFunc {
+	if maxInFlight <= 0 {
+		panic(fmt.Sprintf("middlewares: ConcurrencyLimit maxInFlight must be positive, got %d", maxInFlight))
+	}
+
+	o := &concurrencyLimitOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	slots := make(chan struct{}, maxInFlight)
+	var waiting atomic.Int64
+
+	acquire := func(ctx *gin.Context) bool {
+		select {
+		case slots <- struct{}{}:
+			return true
+		default:
+		}
+		if o.queueTimeout <= 0 {
+			return false
+		}
+		if n := waiting.Add(1); o.queueSize > 0 && n > int64(o.queueSize) {
+			waiting.Add(-1)
+			return false
+		}
+		defer waiting.Add(-1)
+
+		timer := time.NewTimer(o.queueTimeout)
+		defer timer.Stop()
+		select {
+		case slots <- struct{}{}:
+			return true
+		case <-timer.C:
+			return false
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		if !acquire(ctx) {
+			ctx.Header("Retry-After", "1")
+			resp.AbortWithError(ctx, resp.ErrServiceUnavailable(fmt.Errorf("concurrency limit of %d requests reached", maxInFlight)))
+			return
+		}
+		defer func() { <-slots }()
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []ConcurrencyLimitOption
+		wantStatus int
+	}{
+		{name: "shed without queue", wantStatus: http.StatusServiceUnavailable},
+		{name: "queue timeout", opts: []ConcurrencyLimitOption{ConcurrencyLimitQueue(1, 10*time.Millisecond)}, wantStatus: http.StatusServiceUnavailable},
+		{name: "queued until a slot frees", opts: []ConcurrencyLimitOption{ConcurrencyLimitQueue(1, time.Second)}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, release := make(chan struct{}), make(chan struct{})
+
+			gin.SetMode(gin.TestMode)
+			engine := gin.New()
+			engine.GET("/slow", ConcurrencyLimit(1, tt.opts...), func(ctx *gin.Context) {
+				if ctx.Query("block") != "" {
+					close(started)
+					<-release
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow?block=1", nil))
+			}()
+			<-started
+
+			if tt.wantStatus == http.StatusOK {
+				time.AfterFunc(20*time.Millisecond, func() { close(release) })
+			} else {
+				defer close(release)
+			}
+
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+				assert.JSONEq(t, `{"status_code":503,"code":"service_unavailable","message":"service unavailable"}`, recorder.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				wg.Wait()
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimitInvalid(t *testing.T) {
+	assert.PanicsWithValue(t, "middlewares: ConcurrencyLimit maxInFlight must be positive, got 0", func() { ConcurrencyLimit(0) })
+	assert.Panics(t, func() { ConcurrencyLimit(-1) })
+}
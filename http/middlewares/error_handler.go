@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ErrorHandler creates a middleware writing the errors handlers record with
+// ctx.Error. When the handler chain wrote no response, the last recorded error
+// is converted by resp.MapError and written with resp.ResponseError; 5xx
+// errors are logged with the request ID. Handlers can therefore return errors
+// from any layer without choosing a status code themselves.
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function writing recorded errors
+//
+// Example:
+//
+//	router.Use(middlewares.Recover(), middlewares.ErrorHandler())
+//	router.GET("/orders/:id", func(ctx *gin.Context) {
+//	    order, err := orderService.Get(ctx.Request.Context(), ctx.Param("id"))
+//	    if err != nil {
+//	        _ = ctx.Error(err) // gorm.ErrRecordNotFound is written as a 404
+//	        return
+//	    }
+//	    resp.ResponseSuccess(ctx, resp.NewSuccessResp("success", order))
+//	})
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		last := ctx.Errors.Last()
+		if last == nil || ctx.Writer.Written() {
+			return
+		}
+
+		errResp := resp.MapError(ctx.Request.Context(), last.Err)
+		if errResp.StatusCode >= http.StatusInternalServerError {
+			logger.FromContext(ctx.Request.Context()).With(zap.String("prefix", "ErrorHandler")).Sugar().Error(last.Err)
+		}
+		resp.ResponseError(ctx, errResp)
+	}
+}
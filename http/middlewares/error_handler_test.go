@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(ErrorHandler())
+	engine.GET("/missing", func(ctx *gin.Context) {
+		_ = ctx.Error(fmt.Errorf("failed to load order: %w", gorm.ErrRecordNotFound))
+	})
+	engine.GET("/broken", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("pq: connection refused"))
+	})
+	engine.GET("/aborted", func(ctx *gin.Context) {
+		resp.AbortWithError(ctx, resp.ErrForbidden(errors.New("denied")))
+	})
+	engine.GET("/written", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("cache write failed"))
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/missing", http.StatusNotFound, `{"status_code":404,"code":"not_found","message":"not found"}`},
+		{"/broken", http.StatusInternalServerError, `{"status_code":500,"code":"internal","message":"internal server error"}`},
+		{"/aborted", http.StatusForbidden, `{"status_code":403,"code":"permission_denied","message":"permission denied"}`},
+		{"/written", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantBody == "" {
+				assert.Equal(t, "ok", recorder.Body.String())
+				return
+			}
+			assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package middlewares
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultETagMaxSize = 1 << 20
+
+// ETagOption is a function type that modifies the ETag middleware configuration.
+type ETagOption func(*etagOptions)
+
+type etagOptions struct {
+	maxSize   int
+	skipPaths []string
+}
+
+// ETagMaxSize returns an ETagOption that sends larger responses without an ETag
+// instead of buffering them. Defaults to 1 MiB.
+//
+// Parameters:
+//   - size: Maximum buffered response size in bytes
+//
+// Returns:
+//   - ETagOption: Function that sets the maximum size
+func ETagMaxSize(size int) ETagOption {
+	return func(o *etagOptions) {
+		o.maxSize = size
+	}
+}
+
+// ETagSkipPaths returns an ETagOption that never adds ETags to the given paths.
+// A path matches the route template or the request path, and a trailing "*"
+// matches any suffix.
+//
+// Parameters:
+//   - paths: Paths to skip, e.g. "/events"
+//
+// Returns:
+//   - ETagOption: Function that adds the skipped paths
+func ETagSkipPaths(paths ...string) ETagOption {
+	return func(o *etagOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// ETag creates a middleware adding a weak ETag, computed from the body, to
+// successful GET and HEAD responses that have none, and answering conditional
+// requests with 304 Not Modified. If-None-Match is compared with the ETag and,
+// when absent, If-Modified-Since with the Last-Modified header set by the
+// handler. Responses are buffered to compute the ETag; responses that are
+// flushed, e.g. by gin.Context.Stream, or exceed the maximum size are sent as
+// is. Register it after Compress so the ETag describes the uncompressed body.
+//
+// Parameters:
+//   - opts: Variable number of ETagOption functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function handling ETags
+//
+// Examples:
+//
+//	router.Use(middlewares.Compress(), middlewares.ETag(middlewares.ETagSkipPaths("/events")))
+func ETag(opts ...ETagOption) gin.HandlerFunc {
+	o := &etagOptions{maxSize: defaultETagMaxSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(ctx *gin.Context) {
+		method := ctx.Request.Method
+		if method != http.MethodGet && method != http.MethodHead || matchPath(o.skipPaths, ctx) {
+			ctx.Next()
+			return
+		}
+
+		writer := &etagWriter{ResponseWriter: ctx.Writer, maxSize: o.maxSize}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+		writer.finish(ctx.Request)
+	}
+}
+
+// etagWriter buffers the response until the handler returns, unless it is
+// flushed or grows beyond maxSize.
+type etagWriter struct {
+	gin.ResponseWriter
+	maxSize     int
+	buffer      []byte
+	passthrough bool
+	written     bool
+	size        int
+}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	w.written = true
+	if w.passthrough {
+		n, err := w.ResponseWriter.Write(data)
+		w.size += n
+		return n, err
+	}
+	if len(w.buffer)+len(data) > w.maxSize {
+		if err := w.release(); err != nil {
+			return 0, err
+		}
+		n, err := w.ResponseWriter.Write(data)
+		w.size += n
+		return n, err
+	}
+	w.buffer = append(w.buffer, data...)
+	w.size += len(data)
+	return len(data), nil
+}
+
+// Written reports whether the handler wrote a response, including buffered bytes.
+func (w *etagWriter) Written() bool {
+	return w.written || w.ResponseWriter.Written()
+}
+
+// Size returns the number of body bytes the handler wrote, including buffered
+// bytes, or -1 if it wrote nothing.
+func (w *etagWriter) Size() int {
+	if !w.Written() {
+		return -1
+	}
+	return w.size
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush gives up on the ETag and streams the response.
+func (w *etagWriter) Flush() {
+	if err := w.release(); err != nil {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
+
+// release switches to passthrough, sending the buffered body.
+func (w *etagWriter) release() error {
+	if w.passthrough {
+		return nil
+	}
+	w.passthrough = true
+	buffer := w.buffer
+	w.buffer = nil
+	if len(buffer) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buffer)
+	return err
+}
+
+// finish sets the ETag and sends either 304 or the buffered body.
+func (w *etagWriter) finish(req *http.Request) {
+	if w.passthrough {
+		return
+	}
+
+	header := w.Header()
+	if status := w.Status(); status >= http.StatusOK && status < http.StatusMultipleChoices {
+		if header.Get("ETag") == "" && len(w.buffer) > 0 {
+			header.Set("ETag", weakETag(w.buffer))
+		}
+		if notModified(req, header) {
+			for _, name := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+				header.Del(name)
+			}
+			w.buffer = nil
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+	}
+	_ = w.release()
+}
+
+// weakETag returns a weak ETag identifying body.
+func weakETag(body []byte) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write(body)
+	return fmt.Sprintf(`W/"%x-%x"`, len(body), hash.Sum64())
+}
+
+// notModified reports whether the conditional headers of req match the response
+// headers. If-Modified-Since is only evaluated without If-None-Match.
+func notModified(req *http.Request, header http.Header) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, header.Get("ETag"))
+	}
+
+	since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// etagMatches reports whether an If-None-Match list matches etag by weak comparison.
+func etagMatches(list, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
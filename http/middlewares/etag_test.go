@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Compress(), ETag(ETagSkipPaths("/skipped")))
+	engine.GET("/items", func(ctx *gin.Context) { ctx.Data(http.StatusOK, "application/json", []byte(largeBody)) })
+	engine.GET("/versioned", func(ctx *gin.Context) {
+		ctx.Header("ETag", `"v2"`)
+		ctx.JSON(http.StatusOK, gin.H{"version": 2})
+	})
+	engine.GET("/modified", func(ctx *gin.Context) {
+		ctx.Header("Last-Modified", modified.Format(http.TimeFormat))
+		ctx.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	engine.GET("/missing", func(ctx *gin.Context) { ctx.JSON(http.StatusNotFound, gin.H{"ok": false}) })
+	engine.GET("/skipped", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"ok": true}) })
+	engine.GET("/stream", func(ctx *gin.Context) {
+		_, _ = ctx.Writer.Write([]byte("chunk"))
+		ctx.Writer.Flush()
+	})
+
+	serve := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := serve("/items", nil)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Regexp(t, `^W/"[0-9a-f]+-[0-9a-f]+"$`, etag)
+	assert.Equal(t, etag, serve("/items", map[string]string{"Accept-Encoding": "gzip"}).Header().Get("ETag"))
+
+	tests := []struct {
+		name       string
+		path       string
+		headers    map[string]string
+		wantStatus int
+		wantETag   string
+	}{
+		{name: "matching etag", path: "/items", headers: map[string]string{"If-None-Match": etag}, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "matching etag in list", path: "/items", headers: map[string]string{"If-None-Match": `"other", ` + etag}, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "matching compressed etag", path: "/items", headers: map[string]string{"If-None-Match": etag, "Accept-Encoding": "gzip"}, wantStatus: http.StatusNotModified, wantETag: etag},
+		{name: "stale etag", path: "/items", headers: map[string]string{"If-None-Match": `W/"stale"`}, wantStatus: http.StatusOK, wantETag: etag},
+		{name: "handler etag", path: "/versioned", headers: map[string]string{"If-None-Match": `W/"v2"`}, wantStatus: http.StatusNotModified, wantETag: `"v2"`},
+		{name: "not modified since", path: "/modified", headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, wantStatus: http.StatusNotModified},
+		{name: "modified since", path: "/modified", headers: map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, wantStatus: http.StatusOK},
+		{name: "error response", path: "/missing", headers: map[string]string{"If-None-Match": "*"}, wantStatus: http.StatusNotFound},
+		{name: "skipped path", path: "/skipped", wantStatus: http.StatusOK},
+		{name: "streamed response", path: "/stream", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(tt.path, tt.headers)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantETag != "" {
+				assert.Equal(t, tt.wantETag, recorder.Header().Get("ETag"))
+			}
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, recorder.Body.String())
+				assert.Empty(t, recorder.Header().Get("Content-Type"))
+				assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+			} else {
+				assert.NotEmpty(t, recorder.Body.String())
+			}
+			if tt.path == "/skipped" || tt.path == "/stream" {
+				assert.Empty(t, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestBufferedWritersWritten(t *testing.T) {
+	for name, middleware := range map[string]gin.HandlerFunc{"etag": ETag(), "compress": Compress()} {
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			engine := gin.New()
+			engine.Use(middleware, Recover(), ErrorHandler())
+			engine.GET("/error", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"ok": true})
+				_ = ctx.Error(errors.New("cache write failed"))
+			})
+			engine.GET("/panic", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"ok": true})
+				assert.True(t, ctx.Writer.Written())
+				assert.Equal(t, len(`{"ok":true}`), ctx.Writer.Size())
+				panic("boom")
+			})
+
+			for _, path := range []string{"/error", "/panic"} {
+				recorder := httptest.NewRecorder()
+				engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+				assert.Equal(t, http.StatusOK, recorder.Code, path)
+				assert.JSONEq(t, `{"ok":true}`, recorder.Body.String(), path)
+			}
+		})
+	}
+}
@@ -1,29 +1,58 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/logger"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// Recover creates a middleware recovering panics of later handlers. Any panic
+// value is accepted: errors are converted by resp.MapError, so panicking with a
+// *resp.ErrorResp still writes it, and other values become a 500. Server
+// errors are logged with the stack trace and request ID. Nothing is written
+// when the handler already started its response; http.ErrAbortHandler is
+// panicked again so net/http aborts the connection.
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function recovering panics
+//
+// Example:
+//
+//	router.Use(middlewares.RequestId(), middlewares.Recover())
 func Recover() gin.HandlerFunc {
-	log := zap.L().With(zap.String("prefix", "recover")).Sugar()
 	return func(ctx *gin.Context) {
 		defer func() {
-			if err := recover(); err != nil {
-				log.Error(err)
+			value := recover()
+			if value == nil {
+				return
+			}
 
-				ctx.Header("Content-Type", "application/json")
+			err, ok := value.(error)
+			if !ok {
+				err = fmt.Errorf("panic: %v", value)
+			}
+			if errors.Is(err, http.ErrAbortHandler) {
+				panic(value)
+			}
 
-				if commonErr, ok := err.(*resp.ErrorResp); ok {
-					ctx.AbortWithStatusJSON(commonErr.StatusCode, commonErr)
-					return
-				}
+			errResp := resp.MapError(ctx.Request.Context(), err)
+			if errResp.StatusCode >= http.StatusInternalServerError {
+				logger.FromContext(ctx.Request.Context()).With(zap.String("prefix", "recover")).Sugar().
+					Errorw("recovered from panic", "error", err, "stack", string(debug.Stack()))
+			}
 
-				ctx.AbortWithStatusJSON(http.StatusInternalServerError, resp.ErrInternalServer(err.(error)))
+			if ctx.Writer.Written() {
+				_ = ctx.Error(err)
+				ctx.Abort()
+				return
 			}
+			resp.AbortWithError(ctx, errResp)
 		}()
 
 		ctx.Next()
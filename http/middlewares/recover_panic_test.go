@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "string",
+			handler:    func(*gin.Context) { panic("boom") },
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"status_code":500,"code":"internal","message":"internal server error"}`,
+		},
+		{
+			name:       "error",
+			handler:    func(*gin.Context) { panic(errors.New("pq: connection refused")) },
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"status_code":500,"code":"internal","message":"internal server error"}`,
+		},
+		{
+			name:       "mapped error",
+			handler:    func(*gin.Context) { panic(gorm.ErrRecordNotFound) },
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"status_code":404,"code":"not_found","message":"not found"}`,
+		},
+		{
+			name:       "error response",
+			handler:    func(*gin.Context) { panic(resp.ErrForbidden(errors.New("denied"))) },
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"status_code":403,"code":"permission_denied","message":"permission denied"}`,
+		},
+		{
+			name: "after the response started",
+			handler: func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, "partial")
+				panic("boom")
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "partial",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			engine := gin.New()
+			engine.Use(Recover())
+			engine.GET("/", tt.handler)
+
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantBody, recorder.Body.String())
+				return
+			}
+			assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Recover())
+	engine.GET("/", func(*gin.Context) { panic(http.ErrAbortHandler) })
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -1,16 +1,111 @@
 package middlewares
 
 import (
-	"context"
-
-	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/anthanhphan/saturday/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderRequestId is the default header carrying the request ID
+	HeaderRequestId = "X-Request-ID"
+	// HeaderTraceParent is the W3C Trace Context header
+	HeaderTraceParent = "traceparent"
+
+	maxRequestIdLength = 128
 )
 
-// RequestId creates a middleware that generates and adds a unique request ID to each request's context.
-// The request ID is generated using UUID v4 and stored in the context using ctxkey.CtxRequestIdKey.
+// RequestIdOption is a function type that modifies the RequestId middleware configuration.
+type RequestIdOption func(*requestIdOptions)
+
+type requestIdOptions struct {
+	headers        []string
+	responseHeader *string
+	trustInbound   bool
+	validate       func(id string) bool
+	generate       func() string
+}
+
+// RequestIdHeaders returns a RequestIdOption that reads the inbound request ID
+// from the first of headers that holds a valid value. The first header is also
+// used for the response unless RequestIdResponseHeader is set.
+//
+// Parameters:
+//   - headers: Header names, e.g. "X-Request-ID", "X-Correlation-ID"
+//
+// Returns:
+//   - RequestIdOption: Function that sets the header names
+func RequestIdHeaders(headers ...string) RequestIdOption {
+	return func(o *requestIdOptions) {
+		o.headers = headers
+	}
+}
+
+// RequestIdResponseHeader returns a RequestIdOption that sets the header echoing
+// the request ID on the response. An empty name disables the echo.
+//
+// Parameters:
+//   - header: Response header name
+//
+// Returns:
+//   - RequestIdOption: Function that sets the response header
+func RequestIdResponseHeader(header string) RequestIdOption {
+	return func(o *requestIdOptions) {
+		o.responseHeader = &header
+	}
+}
+
+// RequestIdIgnoreInbound returns a RequestIdOption that always generates a new
+// request ID, e.g. for services exposed directly to untrusted clients.
+//
+// Returns:
+//   - RequestIdOption: Function that disables inbound request IDs
+func RequestIdIgnoreInbound() RequestIdOption {
+	return func(o *requestIdOptions) {
+		o.trustInbound = false
+	}
+}
+
+// RequestIdValidator returns a RequestIdOption that replaces ValidRequestId as
+// the check applied to inbound request IDs. Invalid IDs are replaced by a new one.
+//
+// Parameters:
+//   - validate: Function reporting whether an inbound ID is acceptable
+//
+// Returns:
+//   - RequestIdOption: Function that sets the validator
+func RequestIdValidator(validate func(id string) bool) RequestIdOption {
+	return func(o *requestIdOptions) {
+		o.validate = validate
+	}
+}
+
+// RequestIdGenerator returns a RequestIdOption that sets the generator of new
+// request IDs. Defaults to NewUUIDv4.
+//
+// Parameters:
+//   - generate: Generator, e.g. NewUUIDv4, NewUUIDv7 or NewULID
+//
+// Returns:
+//   - RequestIdOption: Function that sets the generator
+func RequestIdGenerator(generate func() string) RequestIdOption {
+	return func(o *requestIdOptions) {
+		o.generate = generate
+	}
+}
+
+// RequestId creates a middleware that assigns a request ID to each request.
+// A valid inbound ID from the configured headers (X-Request-ID by default) is
+// reused, otherwise a new one is generated. The ID is stored in the request
+// context under ctxkey.CtxRequestIdKey and echoed in the response header.
+// A valid W3C traceparent header is parsed and stored under ctxkey.CtxTraceParentKey,
+// so both flow into logger.FromContext and outgoing Kafka messages.
+//
+// Parameters:
+//   - opts: Variable number of RequestIdOption functions
 //
 // Returns:
 //   - gin.HandlerFunc: Middleware function that adds request ID to context
@@ -18,12 +113,106 @@ import (
 // Examples:
 //
 //	router := gin.New()
-//	router.Use(RequestId())
-//	// Each request will now have a unique ID accessible via context
-//	// Access ID in handlers: ctx.Request.Context().Value(ctxkey.CtxRequestIdKey).(string)
-func RequestId() gin.HandlerFunc {
+//	router.Use(RequestId(
+//	    RequestIdHeaders("X-Request-ID", "X-Correlation-ID"),
+//	    RequestIdGenerator(NewUUIDv7),
+//	))
+//	// Access ID in handlers: metadata.GetRequestId(ctx.Request.Context())
+func RequestId(opts ...RequestIdOption) gin.HandlerFunc {
+	o := &requestIdOptions{
+		headers:      []string{HeaderRequestId},
+		trustInbound: true,
+		validate:     ValidRequestId,
+		generate:     NewUUIDv4,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	responseHeader := ""
+	switch {
+	case o.responseHeader != nil:
+		responseHeader = *o.responseHeader
+	case len(o.headers) > 0:
+		responseHeader = o.headers[0]
+	}
+
 	return func(ctx *gin.Context) {
-		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), ctxkey.CtxRequestIdKey, uuid.New().String()))
+		requestId := o.inbound(ctx)
+		if requestId == "" {
+			requestId = o.generate()
+		}
+
+		c := metadata.SetRequestId(ctx.Request.Context(), requestId)
+		if value := ctx.GetHeader(HeaderTraceParent); value != "" {
+			if tp, err := metadata.ParseTraceParent(value); err == nil {
+				c = metadata.SetTraceParent(c, tp)
+			}
+		}
+		ctx.Request = ctx.Request.WithContext(c)
+
+		if responseHeader != "" {
+			ctx.Header(responseHeader, requestId)
+		}
 		ctx.Next()
 	}
 }
+
+// inbound returns the first valid request ID of the configured headers.
+func (o *requestIdOptions) inbound(ctx *gin.Context) string {
+	if !o.trustInbound {
+		return ""
+	}
+	for _, header := range o.headers {
+		if id := ctx.GetHeader(header); id != "" && o.validate(id) {
+			return id
+		}
+	}
+	return ""
+}
+
+// ValidRequestId reports whether id is at most 128 characters of letters,
+// digits and "-", "_", ".", ":", so inbound IDs cannot inject into logs or headers.
+//
+// Parameters:
+//   - id: The inbound request ID
+//
+// Returns:
+//   - bool: true if id is acceptable
+func ValidRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewUUIDv4 generates a random UUID version 4 request ID.
+func NewUUIDv4() string {
+	return uuid.New().String()
+}
+
+// NewUUIDv7 generates a time-ordered UUID version 7 request ID.
+func NewUUIDv7() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return NewUUIDv4()
+	}
+	return id.String()
+}
+
+// NewULID generates a time-ordered ULID request ID.
+func NewULID() string {
+	id, err := utils.NewULID()
+	if err != nil {
+		zap.L().With(zap.String("prefix", "NewULID")).Sugar().Warnf("failed to generate ulid: %v", err)
+		return NewUUIDv4()
+	}
+	return id
+}
@@ -0,0 +1,134 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([47])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestId(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []RequestIdOption
+		headers        map[string]string
+		responseHeader string
+		wantId         string
+		wantTraceId    string
+		check          func(t *testing.T, id string)
+	}{
+		{
+			name:           "generates uuid v4",
+			responseHeader: HeaderRequestId,
+			check: func(t *testing.T, id string) {
+				assert.Equal(t, "4", uuidPattern.FindStringSubmatch(id)[1])
+			},
+		},
+		{
+			name:           "reuses valid inbound id",
+			headers:        map[string]string{HeaderRequestId: "gateway-123"},
+			responseHeader: HeaderRequestId,
+			wantId:         "gateway-123",
+		},
+		{
+			name:           "replaces invalid inbound id",
+			headers:        map[string]string{HeaderRequestId: "bad id\nforged=1"},
+			responseHeader: HeaderRequestId,
+			check: func(t *testing.T, id string) {
+				assert.Regexp(t, uuidPattern, id)
+			},
+		},
+		{
+			name:           "ignores inbound id",
+			opts:           []RequestIdOption{RequestIdIgnoreInbound()},
+			headers:        map[string]string{HeaderRequestId: "gateway-123"},
+			responseHeader: HeaderRequestId,
+			check: func(t *testing.T, id string) {
+				assert.NotEqual(t, "gateway-123", id)
+			},
+		},
+		{
+			name:           "custom headers",
+			opts:           []RequestIdOption{RequestIdHeaders("X-Correlation-ID", HeaderRequestId)},
+			headers:        map[string]string{HeaderRequestId: "second"},
+			responseHeader: "X-Correlation-ID",
+			wantId:         "second",
+		},
+		{
+			name:           "custom validator",
+			opts:           []RequestIdOption{RequestIdValidator(func(id string) bool { return strings.HasPrefix(id, "gw-") })},
+			headers:        map[string]string{HeaderRequestId: "gw-1"},
+			responseHeader: HeaderRequestId,
+			wantId:         "gw-1",
+		},
+		{
+			name:           "uuid v7 generator",
+			opts:           []RequestIdOption{RequestIdGenerator(NewUUIDv7)},
+			responseHeader: HeaderRequestId,
+			check: func(t *testing.T, id string) {
+				assert.Equal(t, "7", uuidPattern.FindStringSubmatch(id)[1])
+			},
+		},
+		{
+			name:           "ulid generator",
+			opts:           []RequestIdOption{RequestIdGenerator(NewULID)},
+			responseHeader: HeaderRequestId,
+			check: func(t *testing.T, id string) {
+				assert.Len(t, id, 26)
+			},
+		},
+		{
+			name:           "parses traceparent",
+			headers:        map[string]string{HeaderTraceParent: testTraceParent},
+			responseHeader: HeaderRequestId,
+			wantTraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:           "ignores malformed traceparent",
+			headers:        map[string]string{HeaderTraceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			responseHeader: HeaderRequestId,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			engine := gin.New()
+			engine.Use(RequestId(tt.opts...))
+
+			var gotId, gotTraceId string
+			engine.GET("/", func(ctx *gin.Context) {
+				gotId = metadata.GetRequestId(ctx.Request.Context())
+				if tp, ok := metadata.GetTraceParent(ctx.Request.Context()); ok {
+					gotTraceId = tp.TraceId
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			assert.NotEmpty(t, gotId)
+			assert.Equal(t, gotId, recorder.Header().Get(tt.responseHeader))
+			if tt.wantId != "" {
+				assert.Equal(t, tt.wantId, gotId)
+			}
+			if tt.check != nil {
+				tt.check(t, gotId)
+			}
+			assert.Equal(t, tt.wantTraceId, gotTraceId)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout creates a middleware giving the handler a deadline through
+// ctx.Request.Context(). Database queries, Kafka writes and outgoing requests
+// using that context are cancelled once it expires. Handlers returning after
+// the deadline without writing a response are answered with a 504; route.Typed
+// answers errors wrapping context.DeadlineExceeded with a 504 too. Add it to the
+// Middlewares of a route.Route or route.GroupRoute; the earliest deadline of
+// nested middlewares applies.
+//
+// Parameters:
+//   - timeout: Time the handler may take
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function setting the deadline
+//
+// Example:
+//
+//	route.GroupRoute{
+//	    Prefix:      "/reports",
+//	    Middlewares: []func(*gin.Context){middlewares.Timeout(30 * time.Second)},
+//	    Routes:      reportRoutes,
+//	}
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(c)
+		ctx.Next()
+
+		if errors.Is(c.Err(), context.DeadlineExceeded) && !ctx.Writer.Written() {
+			resp.AbortWithError(ctx, resp.ErrGatewayTimeout(fmt.Errorf("request exceeded its deadline of %s", timeout)))
+		}
+	}
+}
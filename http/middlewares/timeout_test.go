@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Timeout(20 * time.Millisecond))
+	engine.GET("/fast", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	engine.GET("/slow", func(ctx *gin.Context) { <-ctx.Request.Context().Done() })
+	engine.GET("/typed", route.Typed(func(ctx context.Context, _ struct{}) (string, error) {
+		<-ctx.Done()
+		return "", fmt.Errorf("failed to load report: %w", ctx.Err())
+	}))
+
+	for path, wantStatus := range map[string]int{
+		"/fast":  http.StatusOK,
+		"/slow":  http.StatusGatewayTimeout,
+		"/typed": http.StatusGatewayTimeout,
+	} {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, wantStatus, recorder.Code, path)
+		if wantStatus == http.StatusGatewayTimeout {
+			assert.JSONEq(t, `{"status_code":504,"code":"timeout","message":"request timed out"}`, recorder.Body.String(), path)
+		}
+	}
+}
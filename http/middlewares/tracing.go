@@ -0,0 +1,96 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/anthanhphan/saturday/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingOption is a function type that modifies the Tracing middleware configuration.
+type TracingOption func(*tracingOptions)
+
+type tracingOptions struct {
+	skipPaths []string
+}
+
+// TracingSkipPaths returns a TracingOption that never traces the given paths.
+// A path matches the route template or the request path, and a trailing "*"
+// matches any suffix.
+//
+// Parameters:
+//   - paths: Paths to skip, e.g. "/health-check"
+//
+// Returns:
+//   - TracingOption: Function that adds the skipped paths
+func TracingSkipPaths(paths ...string) TracingOption {
+	return func(o *tracingOptions) {
+		o.skipPaths = append(o.skipPaths, paths...)
+	}
+}
+
+// Tracing creates a middleware that runs each request in a server span named
+// after the method and route template. The span continues the trace of the
+// inbound traceparent header, and its context is stored in the request context
+// so database queries and published Kafka messages become its children.
+// Responses with a 5xx status mark the span as failed.
+//
+// Parameters:
+//   - opts: Variable number of TracingOption functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function tracing the request
+//
+// Examples:
+//
+//	provider, undo := tracing.InitTracer(&tracing.Config{ServiceName: "orders"}, exporter)
+//	router.Use(middlewares.RequestId(), middlewares.Tracing(middlewares.TracingSkipPaths("/health-check")))
+func Tracing(opts ...TracingOption) gin.HandlerFunc {
+	o := &tracingOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(ctx *gin.Context) {
+		if matchPath(o.skipPaths, ctx) {
+			ctx.Next()
+			return
+		}
+
+		route := ctx.FullPath()
+		name := ctx.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		c := tracing.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		c, span := tracing.Start(c, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", ctx.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", ctx.Request.URL.Path),
+				attribute.String("client.address", ctx.ClientIP()),
+				attribute.String("user_agent.original", ctx.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+		ctx.Request = ctx.Request.WithContext(c)
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if len(ctx.Errors) > 0 {
+			span.RecordError(ctx.Errors.Last())
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d %s", status, http.StatusText(status)))
+		}
+	}
+}
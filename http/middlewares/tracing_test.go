@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthanhphan/saturday/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, undo := tracing.InitTracer(&tracing.Config{ServiceName: "test"}, exporter)
+	defer undo()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RequestId(), Tracing(TracingSkipPaths("/health-check")))
+	engine.GET("/users/:id", func(ctx *gin.Context) {
+		_, span := tracing.Start(ctx.Request.Context(), "load user")
+		span.End()
+		ctx.Status(http.StatusOK)
+	})
+	engine.GET("/fail", func(ctx *gin.Context) { ctx.Status(http.StatusBadGateway) })
+	engine.GET("/health-check", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	for _, path := range []string{"/users/7", "/fail", "/health-check"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(HeaderTraceParent, testTraceParent)
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	child, server, failed := spans[0], spans[1], spans[2]
+	assert.Equal(t, "GET /users/:id", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+
+	assert.Equal(t, "GET /fail", failed.Name)
+	assert.Equal(t, codes.Error, failed.Status.Code)
+}
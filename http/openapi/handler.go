@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+//go:embed docs.html
+var docsPage string
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsPage))
+
+// uiAssets are the swagger-ui-dist 5.18.2 assets loaded by the docs page.
+//
+//go:embed swagger-ui
+var uiAssets embed.FS
+
+// uiFiles are the embedded assets served under the docs path.
+var uiFiles = []string{"swagger-ui-bundle.js", "swagger-ui.css"}
+
+// AddDocsRoutes creates a GinOption that serves the document generated from
+// routes and groups at cfg.Path, and the Swagger UI docs page at cfg.DocsPath.
+// The Swagger UI assets are embedded and served under cfg.DocsPath, so the page
+// works offline, unless cfg.UIURL points elsewhere. The document is generated
+// once, when the option is applied.
+//
+// Parameters:
+//   - cfg: Document metadata and serving paths
+//   - routes: Individual routes
+//   - groups: Grouped routes
+//
+// Returns:
+//   - route.GinOption: Function that registers the docs routes
+//
+// Example:
+//
+//	engine := route.NewGinEngine(
+//	    route.AddRoutes(routes),
+//	    openapi.AddDocsRoutes(openapi.Config{Title: "Order API"}, routes, nil),
+//	)
+//	// GET /openapi.json returns the document, GET /docs renders it
+func AddDocsRoutes(cfg Config, routes []route.Route, groups []route.GroupRoute) route.GinOption {
+	return func(g *gin.Engine) {
+		log := zap.L().With(zap.String("prefix", "AddDocsRoutes")).Sugar()
+
+		cfg = cfg.withDefaults()
+		spec, err := json.Marshal(Generate(cfg, routes, groups))
+		if err != nil {
+			log.Errorf("failed to encode openapi document: %v", err)
+			return
+		}
+
+		g.GET(cfg.Path, func(ctx *gin.Context) {
+			ctx.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+		})
+
+		if cfg.DocsPath == "-" {
+			return
+		}
+
+		uiURL := cfg.UIURL
+		if uiURL == "" {
+			uiURL = strings.TrimSuffix(cfg.DocsPath, "/") + "/swagger-ui"
+			assets, err := fs.Sub(uiAssets, "swagger-ui")
+			if err != nil {
+				log.Errorf("failed to load swagger ui assets: %v", err)
+				return
+			}
+			for _, name := range uiFiles {
+				g.StaticFileFS(uiURL+"/"+name, name, http.FS(assets))
+			}
+		}
+
+		var page bytes.Buffer
+		if err := docsTemplate.Execute(&page, map[string]string{
+			"Title":   cfg.Title,
+			"UIURL":   uiURL,
+			"SpecURL": cfg.Path,
+		}); err != nil {
+			log.Errorf("failed to render docs page: %v", err)
+			return
+		}
+
+		g.GET(cfg.DocsPath, func(ctx *gin.Context) {
+			ctx.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+		})
+	}
+}
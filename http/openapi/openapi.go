@@ -0,0 +1,244 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/http/route"
+)
+
+const (
+	Version = "3.0.3"
+
+	defaultPath     = "/openapi.json"
+	defaultDocsPath = "/docs"
+	jsonContentType = "application/json"
+)
+
+// Config configures the generated document and where it is served.
+//
+// Fields:
+//   - Title: API title (defaults to "API")
+//   - Description: API description
+//   - Version: API version (defaults to "1.0.0")
+//   - Servers: Base URLs of the API
+//   - Path: Path serving the JSON document (defaults to "/openapi.json")
+//   - DocsPath: Path serving the docs UI (defaults to "/docs"; "-" disables the UI)
+//   - UIURL: Base URL of the swagger-ui-dist assets loaded by the docs page (defaults
+//     to the assets embedded in the binary, served under DocsPath)
+type Config struct {
+	Title       string
+	Description string
+	Version     string
+	Servers     []Server
+	Path        string
+	DocsPath    string
+	UIURL       string
+}
+
+// withDefaults returns a copy of c with empty fields set to their defaults.
+func (c Config) withDefaults() Config {
+	if c.Title == "" {
+		c.Title = "API"
+	}
+	if c.Version == "" {
+		c.Version = "1.0.0"
+	}
+	if c.Path == "" {
+		c.Path = defaultPath
+	}
+	if c.DocsPath == "" {
+		c.DocsPath = defaultDocsPath
+	}
+	return c
+}
+
+// Generate builds an OpenAPI 3 document from routes and group routes. Request
+// and response schemas are generated by reflecting over the types in route.Doc.
+//
+// Parameters:
+//   - cfg: Document metadata
+//   - routes: Individual routes
+//   - groups: Grouped routes
+//
+// Returns:
+//   - *Document: The generated document
+//
+// Example:
+//
+//	doc := openapi.Generate(openapi.Config{Title: "Order API"}, nil, []route.GroupRoute{{
+//	    Prefix: "/api/v1",
+//	    Routes: []route.Route{{
+//	        Path:    "/orders/:id",
+//	        Method:  method.GET,
+//	        Handler: getOrder,
+//	        Doc:     &route.Doc{Summary: "Get an order", Params: OrderUri{}, Response: Order{}},
+//	    }},
+//	}})
+func Generate(cfg Config, routes []route.Route, groups []route.GroupRoute) *Document {
+	cfg = cfg.withDefaults()
+	g := &generator{
+		registry: newSchemaRegistry(),
+		doc: &Document{
+			OpenAPI: Version,
+			Info: Info{
+				Title:       cfg.Title,
+				Description: cfg.Description,
+				Version:     cfg.Version,
+			},
+			Servers: cfg.Servers,
+			Paths:   map[string]PathItem{},
+		},
+	}
+
+	for _, r := range routes {
+		g.addRoute("", nil, r)
+	}
+	for _, group := range groups {
+		for _, r := range group.Routes {
+			g.addRoute(group.Prefix, group.Tags, r)
+		}
+	}
+
+	if len(g.registry.schemas) > 0 {
+		g.doc.Components.Schemas = g.registry.schemas
+	}
+	return g.doc
+}
+
+type generator struct {
+	registry *schemaRegistry
+	doc      *Document
+}
+
+// addRoute adds the operation of r to the document.
+func (g *generator) addRoute(prefix string, groupTags []string, r route.Route) {
+	doc := r.Doc
+	if doc == nil {
+		doc = &route.Doc{}
+	}
+	if doc.Hidden || r.Method.String() == "" {
+		return
+	}
+
+	path, pathParams := convertPath(joinPaths(prefix, r.Path))
+	method := strings.ToLower(r.Method.String())
+
+	operation := &Operation{
+		OperationID: doc.OperationID,
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        append(append([]string(nil), groupTags...), doc.Tags...),
+		Deprecated:  doc.Deprecated,
+		Responses:   map[string]Response{},
+	}
+	if operation.OperationID == "" {
+		operation.OperationID = operationID(method, path)
+	}
+
+	operation.Parameters = g.registry.parameters(doc.Params, "path", "uri")
+	for _, name := range pathParams {
+		if !hasParameter(operation.Parameters, name) {
+			operation.Parameters = append(operation.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	operation.Parameters = append(operation.Parameters, g.registry.parameters(doc.Query, "query", "form")...)
+	operation.Parameters = append(operation.Parameters, g.registry.parameters(doc.Headers, "header", "header")...)
+
+	if schema := g.registry.schemaOf(doc.Body); schema != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{jsonContentType: {Schema: schema}},
+		}
+	}
+
+	status := doc.SuccessStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	operation.Responses[strconv.Itoa(status)] = g.successResponse(status, doc.Response)
+
+	for code, errType := range doc.Errors {
+		if errType == nil {
+			errType = resp.ErrorResp{}
+		}
+		operation.Responses[strconv.Itoa(code)] = Response{
+			Description: http.StatusText(code),
+			Content:     map[string]MediaType{jsonContentType: {Schema: g.registry.schemaOf(errType)}},
+		}
+	}
+
+	item := g.doc.Paths[path]
+	if item == nil {
+		item = PathItem{}
+		g.doc.Paths[path] = item
+	}
+	item[method] = operation
+}
+
+// successResponse describes a resp.SuccessResp whose metadata has the type of metadata.
+func (g *generator) successResponse(status int, metadata any) Response {
+	schema := g.registry.schemaOf(resp.SuccessResp{})
+	if metadataSchema := g.registry.schemaOf(metadata); metadataSchema != nil {
+		schema = &Schema{AllOf: []*Schema{schema, {
+			Type:       "object",
+			Properties: map[string]*Schema{"metadata": metadataSchema},
+		}}}
+	}
+
+	return Response{
+		Description: http.StatusText(status),
+		Content:     map[string]MediaType{jsonContentType: {Schema: schema}},
+	}
+}
+
+// joinPaths joins a group prefix and a route path as gin does.
+func joinPaths(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// convertPath converts gin path parameters (:id, *path) to OpenAPI templates ({id})
+// and returns their names.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives an identifier such as "getApiV1OrdersId" from a method and path.
+func operationID(method, path string) string {
+	words := strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var builder strings.Builder
+	builder.WriteString(method)
+	for _, word := range words {
+		builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return builder.String()
+}
+
+func hasParameter(parameters []Parameter, name string) bool {
+	for _, parameter := range parameters {
+		if parameter.Name == name {
+			return true
+		}
+	}
+	return false
+}
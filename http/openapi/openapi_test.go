@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/method"
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderUri struct {
+	ID int64 `uri:"id" description:"order id"`
+}
+
+type orderQuery struct {
+	Expand []string `form:"expand"`
+	Limit  int      `form:"limit" binding:"required,min=1,max=100"`
+}
+
+type createOrder struct {
+	Email string    `json:"email" binding:"required,email"`
+	Items []item    `json:"items" validate:"required,min=1,dive"`
+	Note  *string   `json:"note,omitempty" example:"leave at door"`
+	At    time.Time `json:"at"`
+}
+
+type item struct {
+	Sku      string `json:"sku"`
+	Quantity int    `json:"quantity" validate:"oneof=1 2 3"`
+}
+
+type order struct {
+	createOrder
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	secret string
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func testRoutes() ([]route.Route, []route.GroupRoute) {
+	handler := func(*gin.Context) {}
+	routes := []route.Route{
+		{Path: "/ping", Method: method.GET, Handler: handler},
+		{Path: "/internal", Method: method.GET, Handler: handler, Doc: &route.Doc{Hidden: true}},
+	}
+	groups := []route.GroupRoute{{
+		Prefix: "/api/v1/",
+		Tags:   []string{"orders"},
+		Routes: []route.Route{
+			{
+				Path:    "/orders/:id/*file",
+				Method:  method.GET,
+				Handler: handler,
+				Doc: &route.Doc{
+					Summary:  "Get an order",
+					Params:   orderUri{},
+					Response: order{},
+					Errors:   map[int]any{http.StatusNotFound: nil},
+				},
+			},
+			{
+				Path:    "/orders",
+				Method:  method.POST,
+				Handler: handler,
+				Doc: &route.Doc{
+					OperationID:   "createOrder",
+					Query:         orderQuery{},
+					Body:          createOrder{},
+					Response:      page[order]{},
+					SuccessStatus: http.StatusCreated,
+				},
+			},
+		},
+	}}
+	return routes, groups
+}
+
+func TestGenerate(t *testing.T) {
+	routes, groups := testRoutes()
+	doc := Generate(Config{Title: "Order API"}, routes, groups)
+
+	assert.Equal(t, "Order API", doc.Info.Title)
+	assert.Equal(t, "1.0.0", doc.Info.Version)
+	assert.Contains(t, doc.Paths, "/ping")
+	assert.NotContains(t, doc.Paths, "/internal")
+
+	get := doc.Paths["/api/v1/orders/{id}/{file}"]["get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "getApiV1OrdersIdFile", get.OperationID)
+	assert.Equal(t, []string{"orders"}, get.Tags)
+	assert.Equal(t, []Parameter{
+		{Name: "id", In: "path", Description: "order id", Required: true, Schema: &Schema{Type: "integer", Format: "int64"}},
+		{Name: "file", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+	}, get.Parameters)
+	assert.Equal(t, "#/components/schemas/ErrorResp", get.Responses["404"].Content[jsonContentType].Schema.Ref)
+
+	success := get.Responses["200"].Content[jsonContentType].Schema
+	require.Len(t, success.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/SuccessResp", success.AllOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/Order", success.AllOf[1].Properties["metadata"].Ref)
+
+	post := doc.Paths["/api/v1/orders"]["post"]
+	require.NotNil(t, post)
+	assert.Equal(t, "createOrder", post.OperationID)
+	assert.Contains(t, post.Responses, "201")
+	require.Len(t, post.Parameters, 2)
+	assert.Equal(t, "query", post.Parameters[1].In)
+	assert.True(t, post.Parameters[1].Required)
+	assert.Equal(t, float64(100), *post.Parameters[1].Schema.Maximum)
+	assert.Equal(t, "#/components/schemas/CreateOrder", post.RequestBody.Content[jsonContentType].Schema.Ref)
+
+	schemas := doc.Components.Schemas
+	assert.Contains(t, schemas, "PageOrder")
+
+	create := schemas["CreateOrder"]
+	assert.Equal(t, []string{"email", "items"}, create.Required)
+	assert.Equal(t, "email", create.Properties["email"].Format)
+	assert.Equal(t, int64(1), *create.Properties["items"].MinItems)
+	assert.Equal(t, "leave at door", create.Properties["note"].Example)
+	assert.True(t, create.Properties["note"].Nullable)
+	assert.Equal(t, "date-time", create.Properties["at"].Format)
+	assert.Equal(t, []any{int64(1), int64(2), int64(3)}, schemas["Item"].Properties["quantity"].Enum)
+
+	assert.ElementsMatch(t, []string{"email", "items", "note", "at", "id", "status"}, keys(schemas["Order"].Properties))
+}
+
+func TestSchemaExtensions(t *testing.T) {
+	routes, groups := testRoutes()
+	data, err := json.Marshal(Generate(Config{}, routes, groups).Components.Schemas["SuccessResp"])
+	require.NoError(t, err)
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, map[string]any{"type": "integer", "format": "int64", "x-order": float64(1)}, schema.Properties["status_code"])
+	assert.Equal(t, map[string]any{"x-order": float64(3)}, schema.Properties["metadata"])
+}
+
+func TestAddDocsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	routes, groups := testRoutes()
+	engine := route.NewGinEngine(AddDocsRoutes(Config{Title: "Order API", Path: "/spec.json"}, routes, groups))
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/spec.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var doc Document
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &doc))
+	assert.Equal(t, Version, doc.OpenAPI)
+
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `url: "\/spec.json"`)
+	assert.Contains(t, recorder.Body.String(), `src="/docs/swagger-ui/swagger-ui-bundle.js"`)
+
+	for _, path := range []string{"/docs/swagger-ui/swagger-ui-bundle.js", "/docs/swagger-ui/swagger-ui.css"} {
+		recorder = httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, recorder.Code, path)
+		assert.NotEmpty(t, recorder.Body.Bytes(), path)
+	}
+
+	// Assets loaded from elsewhere are not served
+	engine = route.NewGinEngine(AddDocsRoutes(Config{UIURL: "https://cdn.example.com/swagger-ui"}, routes, groups))
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Contains(t, recorder.Body.String(), `href="https://cdn.example.com/swagger-ui/swagger-ui.css"`)
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/docs/swagger-ui/swagger-ui.css", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func keys(m map[string]*Schema) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,362 @@
+package openapi
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaRegistry generates schemas by reflection and collects named struct
+// schemas as reusable components.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// schemaOf returns the schema of v's type, or nil for a nil value.
+func (r *schemaRegistry) schemaOf(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	if t, ok := v.(reflect.Type); ok {
+		return r.schema(t)
+	}
+	return r.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of t. Named structs are registered as components
+// and referenced with $ref.
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "duration in nanoseconds", Nullable: nullable}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		// Encoded as JSON strings, e.g. uuid.UUID or net.IP.
+		return &Schema{Type: "string", Nullable: nullable}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float", Nullable: nullable}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem()), Nullable: nullable}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem()), Nullable: nullable}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + r.register(t)}
+	default:
+		// Interfaces and other kinds accept any value.
+		return &Schema{}
+	}
+}
+
+// register adds the schema of the named struct t to the components and returns its name.
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := schemaName(t)
+	for i := 2; r.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", schemaName(t), i)
+	}
+
+	// Reserve the name before recursing so self-referencing types terminate.
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+// structSchema builds an object schema from the exported fields of t.
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the fields of t to s, flattening embedded structs as encoding/json does.
+func (r *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		if field.Tag.Get("json") == "" && isParameter(field) {
+			// Bound from the path, query or headers rather than the body.
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(s, embedded)
+				continue
+			}
+		}
+
+		property := r.schema(field.Type)
+		if property.Ref != "" && hasFieldDocs(field) {
+			// Siblings of $ref are ignored in OpenAPI 3.0, so wrap the reference.
+			property = &Schema{AllOf: []*Schema{property}}
+		}
+		applyFieldTags(property, field)
+		s.Properties[name] = property
+
+		if isRequired(field) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// jsonName returns the JSON property name of field and whether it is skipped.
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", true
+	}
+
+	name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" && opts == "" {
+		return "", true
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+// isParameter reports whether field is bound from the path, query or headers.
+func isParameter(field reflect.StructField) bool {
+	return field.Tag.Get("uri") != "" || field.Tag.Get("form") != "" || field.Tag.Get("header") != ""
+}
+
+// isRequired reports whether field is required by its binding or validate tag.
+func isRequired(field reflect.StructField) bool {
+	for _, key := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(field.Tag.Get(key), ",") {
+			if rule == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasFieldDocs(field reflect.StructField) bool {
+	return field.Tag.Get("description") != "" || field.Tag.Get("example") != "" || field.Tag.Get("extensions") != ""
+}
+
+// applyFieldTags applies the description, example, extensions and validation
+// tags of field to s.
+func applyFieldTags(s *Schema, field reflect.StructField) {
+	if description := field.Tag.Get("description"); description != "" {
+		s.Description = description
+	}
+	if example := field.Tag.Get("example"); example != "" {
+		s.Example = parseValue(s.Type, example)
+	}
+	if extensions := field.Tag.Get("extensions"); extensions != "" {
+		s.Extensions = map[string]any{}
+		for _, extension := range strings.Split(extensions, ",") {
+			key, value, _ := strings.Cut(extension, "=")
+			if strings.HasPrefix(key, "x-") {
+				s.Extensions[key] = parseValue("", value)
+			}
+		}
+	}
+
+	for _, key := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(field.Tag.Get(key), ",") {
+			if rule == "dive" {
+				// Later rules apply to the elements.
+				break
+			}
+			applyRule(s, rule)
+		}
+	}
+}
+
+// applyRule maps a validator rule such as "min=1" or "oneof=a b" to schema constraints.
+func applyRule(s *Schema, rule string) {
+	name, param, _ := strings.Cut(rule, "=")
+	switch name {
+	case "oneof":
+		for _, value := range strings.Fields(param) {
+			s.Enum = append(s.Enum, parseValue(s.Type, value))
+		}
+	case "email":
+		s.Format = "email"
+	case "uuid", "uuid4":
+		s.Format = "uuid"
+	case "url", "uri":
+		s.Format = "uri"
+	case "min", "gte", "max", "lte", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		lower, upper := name == "min" || name == "gte" || name == "len", name == "max" || name == "lte" || name == "len"
+		switch s.Type {
+		case "integer", "number":
+			if lower {
+				s.Minimum = &n
+			}
+			if upper {
+				s.Maximum = &n
+			}
+		case "string":
+			if lower {
+				s.MinLength = ptr(int64(n))
+			}
+			if upper {
+				s.MaxLength = ptr(int64(n))
+			}
+		case "array":
+			if lower {
+				s.MinItems = ptr(int64(n))
+			}
+			if upper {
+				s.MaxItems = ptr(int64(n))
+			}
+		}
+	}
+}
+
+// parseValue converts a tag value to the JSON type of a schema.
+func parseValue(schemaType, value string) any {
+	switch schemaType {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	}
+	return value
+}
+
+// schemaName returns the component name of a named type. Generic types such as
+// Page[github.com/x/models.User] become PageUser.
+func schemaName(t reflect.Type) string {
+	fields := strings.FieldsFunc(t.Name(), func(r rune) bool {
+		return r == '[' || r == ']' || r == ',' || r == '*' || unicode.IsSpace(r)
+	})
+
+	var builder strings.Builder
+	for _, field := range fields {
+		if idx := strings.LastIndex(field, "."); idx >= 0 {
+			field = field[idx+1:]
+		}
+		runes := []rune(field)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// parameters returns the parameters "in" a location described by the fields of v tagged with key.
+func (r *schemaRegistry) parameters(v any, in, key string) []Parameter {
+	if v == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var parameters []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get(key) == "" {
+			parameters = append(parameters, r.parameters(reflect.New(field.Type).Elem().Interface(), in, key)...)
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema := r.schema(field.Type)
+		applyFieldTags(schema, field)
+		description := schema.Description
+		schema.Description = ""
+
+		parameters = append(parameters, Parameter{
+			Name:        name,
+			In:          in,
+			Description: description,
+			Required:    in == "path" || isRequired(field),
+			Schema:      schema,
+		})
+	}
+	return parameters
+}
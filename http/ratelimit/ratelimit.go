@@ -0,0 +1,229 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Rate limit response headers
+const (
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+	HeaderReset      = "X-RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// limiters numbers the middlewares created by New, so limiters stacked on one
+// route keep separate allowances in a shared store.
+var limiters atomic.Int64
+
+// KeyFunc returns the client key of a request. An empty key falls back to the client IP.
+type KeyFunc func(ctx *gin.Context) string
+
+// Option is a function type that modifies the rate limit middleware configuration.
+type Option func(*options)
+
+type options struct {
+	key        KeyFunc
+	scope      string
+	failClosed bool
+}
+
+// WithKey returns an Option that sets how clients are identified. Defaults to ByIP.
+//
+// Parameters:
+//   - key: Key function, e.g. ByIP(), ByUser(), ByAPIKey("X-API-Key") or a custom function
+//
+// Returns:
+//   - Option: Function that sets the key function
+func WithKey(key KeyFunc) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// WithScope returns an Option that shares one allowance between every route
+// using the same scope, including routes limited by other New calls with the
+// same scope and limit. By default each route template has its own allowance
+// within a single New call.
+//
+// Parameters:
+//   - scope: Scope name, e.g. "public-api"
+//
+// Returns:
+//   - Option: Function that sets the scope
+func WithScope(scope string) Option {
+	return func(o *options) {
+		o.scope = scope
+	}
+}
+
+// WithFailClosed returns an Option that rejects requests with 500 when the store
+// fails. By default requests are let through and the failure is logged.
+//
+// Returns:
+//   - Option: Function that makes store failures reject requests
+func WithFailClosed() Option {
+	return func(o *options) {
+		o.failClosed = true
+	}
+}
+
+// ByIP returns a KeyFunc identifying clients by IP address.
+//
+// Returns:
+//   - KeyFunc: The key function
+func ByIP() KeyFunc {
+	return ipKey
+}
+
+// ipKey returns the client IP key of a request.
+func ipKey(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// ByUser returns a KeyFunc identifying clients by the user ID of the requester
+// stored by middlewares.Auth. Anonymous requests fall back to the client IP.
+//
+// Returns:
+//   - KeyFunc: The key function
+func ByUser() KeyFunc {
+	return func(ctx *gin.Context) string {
+		if r := requesterFrom(ctx); r != nil && r.GetUserId() != nil {
+			return fmt.Sprintf("user:%v", r.GetUserId())
+		}
+		return ""
+	}
+}
+
+// ByAPIKey returns a KeyFunc identifying clients by an API key header. Keys are
+// hashed so stores never hold them. Requests without the header fall back to
+// the client IP.
+//
+// Parameters:
+//   - header: Header holding the API key
+//
+// Returns:
+//   - KeyFunc: The key function
+func ByAPIKey(header string) KeyFunc {
+	return func(ctx *gin.Context) string {
+		key := ctx.GetHeader(header)
+		if key == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:16])
+	}
+}
+
+// New creates a middleware that rate limits requests with store. Add it to the
+// Middlewares of a route.Route or route.GroupRoute to limit those routes; each
+// route template gets its own allowance unless WithScope is set, and limiters
+// stacked on a route, e.g. by a group and by the route, never share one. Every response
+// carries X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset, and
+// limited requests abort with a 429 resp.ErrorResp and a Retry-After header.
+// It panics if limit does not allow a positive number of Requests per Window.
+//
+// Parameters:
+//   - store: Store keeping the counts, e.g. NewMemoryStore()
+//   - limit: The limit per client
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function limiting the request
+//
+// Example:
+//
+//	store := ratelimit.NewMemoryStore()
+//	route.GroupRoute{
+//	    Prefix: "/public",
+//	    Middlewares: []func(*gin.Context){
+//	        ratelimit.New(store, ratelimit.Limit{Requests: 100, Window: time.Minute}),
+//	    },
+//	    Routes: []route.Route{{
+//	        Path: "/search", Method: method.GET, Handler: search,
+//	        Middlewares: []func(*gin.Context){ratelimit.New(store,
+//	            ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Requests: 10, Window: time.Second},
+//	            ratelimit.WithKey(ratelimit.ByAPIKey("X-API-Key")),
+//	        )},
+//	    }},
+//	}
+func New(store Store, limit Limit, opts ...Option) gin.HandlerFunc {
+	if err := limit.validate(); err != nil {
+		panic(fmt.Sprintf("ratelimit: invalid limit: %v", err))
+	}
+
+	o := &options{key: ipKey}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	namespace := "limiter:" + strconv.FormatInt(limiters.Add(1), 10)
+	if o.scope != "" {
+		namespace = fmt.Sprintf("scope:%s|%d:%d/%s:%d", o.scope, limit.Algorithm, limit.Requests, limit.Window, limit.Burst)
+	}
+
+	return func(ctx *gin.Context) {
+		key := o.key(ctx)
+		if key == "" {
+			key = ipKey(ctx)
+		}
+		scope := namespace
+		if o.scope == "" {
+			scope += "|" + ctx.Request.Method + " " + ctx.FullPath()
+		}
+
+		result, err := store.Take(ctx.Request.Context(), scope+"|"+key, limit)
+		if err != nil {
+			logger.FromContext(ctx.Request.Context()).With(zap.String("prefix", "RateLimit")).Sugar().
+				Errorf("rate limit store failed: %v", err)
+			if o.failClosed {
+				resp.AbortWithError(ctx, resp.ErrInternalServer(fmt.Errorf("rate limit store failed: %w", err)))
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Header(HeaderLimit, strconv.Itoa(result.Limit))
+		ctx.Header(HeaderRemaining, strconv.Itoa(result.Remaining))
+		ctx.Header(HeaderReset, ceilSeconds(result.Reset))
+		if !result.Allowed {
+			ctx.Header(HeaderRetryAfter, ceilSeconds(max(result.RetryAfter, time.Second)))
+			resp.AbortWithError(ctx, resp.ErrTooManyRequests(fmt.Errorf("rate limit of %d requests per %s exceeded", limit.Requests, limit.Window)))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// ceilSeconds formats a duration as whole seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// requesterFrom returns the requester stored in the gin or request context.
+func requesterFrom(ctx *gin.Context) requester.CtxRequester {
+	if value, ok := ctx.Get(string(ctxkey.CtxRequesterKey)); ok {
+		if r, ok := value.(requester.CtxRequester); ok {
+			return r
+		}
+	}
+	if r, err := metadata.GetRequester(ctx.Request.Context()); err == nil {
+		return r
+	}
+	return nil
+}
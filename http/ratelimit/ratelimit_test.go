@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+	"github.com/anthanhphan/saturday/http/requester"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("database down")
+}
+
+func newTestEngine(middleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		if userId := ctx.GetHeader("X-User"); userId != "" {
+			ctx.Set(string(ctxkey.CtxRequesterKey), requester.NewCtxRequester(userId))
+		}
+	}, middleware)
+	engine.GET("/a", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	engine.GET("/b", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return engine
+}
+
+func serve(engine *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestNew(t *testing.T) {
+	limit := Limit{Requests: 1, Window: time.Minute}
+
+	t.Run("limited request", func(t *testing.T) {
+		engine := newTestEngine(New(NewMemoryStore(), limit))
+
+		first := serve(engine, "/a", nil)
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "1", first.Header().Get(HeaderLimit))
+		assert.Equal(t, "0", first.Header().Get(HeaderRemaining))
+		assert.Equal(t, "60", first.Header().Get(HeaderReset))
+
+		second := serve(engine, "/a", nil)
+		require.Equal(t, http.StatusTooManyRequests, second.Code)
+		assert.Equal(t, "60", second.Header().Get(HeaderRetryAfter))
+
+		var body resp.ErrorResp
+		require.NoError(t, json.Unmarshal(second.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusTooManyRequests, body.StatusCode)
+		assert.Equal(t, "too many requests", body.Message)
+	})
+
+	t.Run("routes have separate allowances", func(t *testing.T) {
+		engine := newTestEngine(New(NewMemoryStore(), limit))
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", nil).Code)
+		assert.Equal(t, http.StatusOK, serve(engine, "/b", nil).Code)
+	})
+
+	t.Run("shared scope", func(t *testing.T) {
+		engine := newTestEngine(New(NewMemoryStore(), limit, WithScope("api")))
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(engine, "/b", nil).Code)
+	})
+
+	t.Run("stacked limiters keep separate allowances", func(t *testing.T) {
+		store := NewMemoryStore()
+		gin.SetMode(gin.TestMode)
+		engine := gin.New()
+		group := engine.Group("/", New(store, Limit{Requests: 3, Window: time.Minute}))
+		group.GET("/a", New(store, Limit{Requests: 2, Window: time.Minute}, WithKey(ByAPIKey("X-API-Key"))),
+			func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+		// Requests without an API key fall back to the client IP in both limiters
+		first := serve(engine, "/a", nil)
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "1", first.Header().Get(HeaderRemaining))
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(engine, "/a", nil).Code)
+
+		// The group limiter allowed three requests by IP, whatever the route limiter decided
+		assert.Equal(t, http.StatusTooManyRequests, serve(engine, "/a", map[string]string{"X-API-Key": "one"}).Code)
+	})
+
+	t.Run("keyed by user", func(t *testing.T) {
+		engine := newTestEngine(New(NewMemoryStore(), limit, WithKey(ByUser())))
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", map[string]string{"X-User": "1"}).Code)
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", map[string]string{"X-User": "2"}).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(engine, "/a", map[string]string{"X-User": "1"}).Code)
+	})
+
+	t.Run("keyed by api key", func(t *testing.T) {
+		engine := newTestEngine(New(NewMemoryStore(), limit, WithKey(ByAPIKey("X-API-Key"))))
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", map[string]string{"X-API-Key": "one"}).Code)
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", map[string]string{"X-API-Key": "two"}).Code)
+		// Requests without a key share the client IP allowance
+		assert.Equal(t, http.StatusOK, serve(engine, "/a", nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(engine, "/a", nil).Code)
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(newTestEngine(New(failingStore{}, limit)), "/a", nil).Code)
+		assert.Equal(t, http.StatusInternalServerError, serve(newTestEngine(New(failingStore{}, limit, WithFailClosed())), "/a", nil).Code)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		assert.PanicsWithValue(t, "ratelimit: invalid limit: limit window must be positive, got 0s", func() {
+			New(NewMemoryStore(), Limit{Requests: 10})
+		})
+		assert.Panics(t, func() { New(NewMemoryStore(), Limit{Window: time.Minute}) })
+		assert.Panics(t, func() { New(NewMemoryStore(), Limit{Requests: -1, Window: time.Minute}) })
+	})
+}
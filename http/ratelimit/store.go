@@ -0,0 +1,233 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Algorithm selects how a Limit counts requests.
+type Algorithm int
+
+const (
+	// TokenBucket refills Requests tokens per Window up to Burst, allowing short bursts.
+	TokenBucket Algorithm = iota
+	// SlidingWindow allows Requests per rolling Window, weighting the previous
+	// window by its overlap with the rolling one.
+	SlidingWindow
+)
+
+// sweepInterval is how often the memory store drops idle keys.
+const sweepInterval = time.Minute
+
+// Limit describes how many requests a key may make.
+//
+// Fields:
+//   - Algorithm: TokenBucket (default) or SlidingWindow
+//   - Requests: Requests allowed per Window
+//   - Window: Length of the window
+//   - Burst: Token bucket capacity; defaults to Requests
+type Limit struct {
+	Algorithm Algorithm
+	Requests  int
+	Window    time.Duration
+	Burst     int
+}
+
+// validate reports an error if the limit cannot be enforced.
+func (l Limit) validate() error {
+	if l.Requests <= 0 {
+		return fmt.Errorf("limit requests must be positive, got %d", l.Requests)
+	}
+	if l.Window <= 0 {
+		return fmt.Errorf("limit window must be positive, got %s", l.Window)
+	}
+	return nil
+}
+
+// Result is the decision of a Store for one request.
+//
+// Fields:
+//   - Allowed: Whether the request may proceed
+//   - Limit: Requests allowed per window, reported in X-RateLimit-Limit
+//   - Remaining: Requests left, reported in X-RateLimit-Remaining
+//   - Reset: Time until the limit is fully restored, reported in X-RateLimit-Reset
+//   - RetryAfter: Time until a denied request may be retried, reported in Retry-After
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the request counts of rate limited keys. Implementations must be
+// safe for concurrent use; stores shared between instances, e.g. backed by
+// postgres.Database, should apply the algorithm atomically in the database.
+type Store interface {
+	// Take counts one request of key against limit and reports whether it is allowed.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore is a Store keeping counts in process memory. Counts are not
+// shared between instances and are lost on restart.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket is the state of a key: tokens for TokenBucket, counts for SlidingWindow.
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	window   int64
+	previous int
+	current  int
+	idle     time.Duration
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+//
+// Returns:
+//   - *MemoryStore: The store
+//
+// Example:
+//
+//	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 100, Window: time.Minute})
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Take counts one request of key against limit.
+//
+// Parameters:
+//   - ctx: Request context
+//   - key: Rate limited key
+//   - limit: The limit
+//
+// Returns:
+//   - Result: The decision
+//   - error: Error if the limit is invalid
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	if err := limit.validate(); err != nil {
+		return Result{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(capacity(limit)), last: now, window: now.UnixNano() / int64(limit.Window)}
+		s.buckets[key] = b
+	}
+	// Idle keys are dropped once they are back to a full allowance
+	b.idle = 2 * limit.Window * time.Duration(max(1, capacity(limit)/max(1, limit.Requests)))
+
+	if limit.Algorithm == SlidingWindow {
+		return b.takeWindow(now, limit), nil
+	}
+	return b.takeToken(now, limit), nil
+}
+
+// takeToken refills the bucket since the last request and takes one token.
+func (b *bucket) takeToken(now time.Time, limit Limit) Result {
+	burst := float64(capacity(limit))
+	rate := float64(limit.Requests) / limit.Window.Seconds()
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((burst - b.tokens) / rate)
+	return result
+}
+
+// takeWindow rolls the fixed windows forward and counts one request if the
+// weighted count of the rolling window stays within the limit.
+func (b *bucket) takeWindow(now time.Time, limit Limit) Result {
+	size := int64(limit.Window)
+	window := now.UnixNano() / size
+	switch {
+	case window == b.window+1:
+		b.previous, b.current = b.current, 0
+	case window > b.window+1:
+		b.previous, b.current = 0, 0
+	}
+	b.window = window
+	b.last = now
+
+	elapsed := time.Duration(now.UnixNano() - window*size)
+	weight := 1 - float64(elapsed)/float64(size)
+	count := float64(b.previous)*weight + float64(b.current)
+
+	result := Result{Limit: limit.Requests, Reset: limit.Window - elapsed}
+	if count+1 <= float64(limit.Requests) {
+		b.current++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = b.retryAfter(elapsed, limit)
+	}
+	result.Remaining = max(0, limit.Requests-int(math.Ceil(count)))
+	return result
+}
+
+// retryAfter returns when the weighted count leaves room for one more request.
+func (b *bucket) retryAfter(elapsed time.Duration, limit Limit) time.Duration {
+	untilNextWindow := limit.Window - elapsed
+	if b.current+1 > limit.Requests || b.previous == 0 {
+		return untilNextWindow
+	}
+
+	// Solve previous*(1-(elapsed+t)/window) + current + 1 = requests for t
+	overlap := float64(limit.Requests-1-b.current) / float64(b.previous)
+	wait := time.Duration((1-overlap)*float64(limit.Window)) - elapsed
+	return min(max(wait, time.Millisecond), untilNextWindow)
+}
+
+// sweep drops idle keys, at most once per sweepInterval.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.last) > b.idle {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// capacity returns the token bucket capacity of limit.
+func capacity(limit Limit) int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	return limit.Requests
+}
+
+// seconds converts a number of seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
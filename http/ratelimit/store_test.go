@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore returns a MemoryStore whose clock is advanced by the returned function.
+func newTestStore() (*MemoryStore, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store, advance := newTestStore()
+	limit := Limit{Requests: 2, Window: time.Second, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		result, err := store.Take(context.Background(), "k", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "burst request %d", i)
+	}
+
+	result, _ := store.Take(context.Background(), "k", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	advance(500 * time.Millisecond)
+	result, _ = store.Take(context.Background(), "k", limit)
+	assert.True(t, result.Allowed)
+
+	// Other keys have their own bucket
+	result, _ = store.Take(context.Background(), "other", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestMemoryStoreSlidingWindow(t *testing.T) {
+	store, advance := newTestStore()
+	limit := Limit{Algorithm: SlidingWindow, Requests: 4, Window: time.Minute}
+
+	for i := 0; i < 4; i++ {
+		result, _ := store.Take(context.Background(), "k", limit)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3-i, result.Remaining)
+	}
+	result, _ := store.Take(context.Background(), "k", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Minute, result.RetryAfter)
+
+	// A quarter into the next window, the previous window still weighs 3 requests
+	advance(75 * time.Second)
+	result, _ = store.Take(context.Background(), "k", limit)
+	assert.True(t, result.Allowed)
+	result, _ = store.Take(context.Background(), "k", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 15*time.Second, result.RetryAfter)
+
+	advance(2 * time.Minute)
+	result, _ = store.Take(context.Background(), "k", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 3, result.Remaining)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store, advance := newTestStore()
+	limit := Limit{Requests: 1, Window: time.Second}
+
+	_, _ = store.Take(context.Background(), "idle", limit)
+	advance(2 * sweepInterval)
+	_, _ = store.Take(context.Background(), "active", limit)
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "active")
+}
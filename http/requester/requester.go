@@ -3,30 +3,83 @@ package requester
 // CtxRequester defines an interface for accessing request context information.
 type CtxRequester interface {
 	GetUserId() any
+	GetRoles() []string
+	GetScopes() []string
+	GetTenantId() any
 }
 
+// Option is a function type that modifies a requester.
+type Option func(*ctxRequester)
+
 var _ CtxRequester = (*ctxRequester)(nil)
 
 type ctxRequester struct {
-	UserId any
+	UserId   any
+	Roles    []string
+	Scopes   []string
+	TenantId any
 }
 
 // NewCtxRequester creates a new CtxRequester instance with the specified user ID.
 //
 // Parameters:
 //   - userId: The user's unique identifier of any type
+//   - options: Variable number of Option functions setting roles, scopes and tenant
 //
 // Returns:
 //   - CtxRequester: A new instance implementing the CtxRequester interface
 //
 // Example:
 //
-//	requester := NewCtxRequester(123)
+//	requester := NewCtxRequester(123, SetRoles("admin"), SetTenantId("acme"))
 //	userId := requester.GetUserId() // returns 123
-func NewCtxRequester(userId any) CtxRequester {
-	return &ctxRequester{
+func NewCtxRequester(userId any, options ...Option) CtxRequester {
+	r := &ctxRequester{
 		UserId: userId,
 	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// SetRoles returns an Option to set the requester's roles.
+//
+// Parameters:
+//   - roles: Role names, e.g. "admin"
+//
+// Returns:
+//   - Option: Function that sets the roles
+func SetRoles(roles ...string) Option {
+	return func(r *ctxRequester) {
+		r.Roles = roles
+	}
+}
+
+// SetScopes returns an Option to set the requester's scopes.
+//
+// Parameters:
+//   - scopes: Granted scopes, e.g. "orders:read"
+//
+// Returns:
+//   - Option: Function that sets the scopes
+func SetScopes(scopes ...string) Option {
+	return func(r *ctxRequester) {
+		r.Scopes = scopes
+	}
+}
+
+// SetTenantId returns an Option to set the requester's tenant.
+//
+// Parameters:
+//   - tenantId: The tenant's unique identifier of any type
+//
+// Returns:
+//   - Option: Function that sets the tenant ID
+func SetTenantId(tenantId any) Option {
+	return func(r *ctxRequester) {
+		r.TenantId = tenantId
+	}
 }
 
 // GetUserId returns the ID of the authenticated user.
@@ -41,3 +94,27 @@ func NewCtxRequester(userId any) CtxRequester {
 func (r *ctxRequester) GetUserId() any {
 	return r.UserId
 }
+
+// GetRoles returns the roles of the authenticated user.
+//
+// Returns:
+//   - []string: The role names
+func (r *ctxRequester) GetRoles() []string {
+	return r.Roles
+}
+
+// GetScopes returns the scopes granted to the request.
+//
+// Returns:
+//   - []string: The scopes
+func (r *ctxRequester) GetScopes() []string {
+	return r.Scopes
+}
+
+// GetTenantId returns the tenant of the authenticated user.
+//
+// Returns:
+//   - any: The tenant's unique identifier, or nil
+func (r *ctxRequester) GetTenantId() any {
+	return r.TenantId
+}
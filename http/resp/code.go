@@ -0,0 +1,145 @@
+package resp
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Error codes of the built-in error helpers. Codes are stable identifiers
+// clients branch on, unlike messages, which may change or be translated.
+const (
+	CodeInvalidRequest        = "invalid_request"
+	CodeValidationFailed      = "validation_failed"
+	CodeUnauthenticated       = "unauthenticated"
+	CodeMissingToken          = "missing_token"
+	CodeInvalidTokenFormat    = "invalid_token_format"
+	CodeInvalidTokenSignature = "invalid_token_signature"
+	CodePermissionDenied      = "permission_denied"
+	CodeNotFound              = "not_found"
+	CodeConflict              = "conflict"
+	CodeRequestTooLarge       = "request_too_large"
+	CodeUnprocessableEntity   = "unprocessable_entity"
+	CodeTooManyRequests       = "too_many_requests"
+	CodeInternal              = "internal"
+	CodeServiceUnavailable    = "service_unavailable"
+	CodeTimeout               = "timeout"
+)
+
+// CodeInfo describes a registered error code.
+//
+// Fields:
+//   - StatusCode: HTTP status code of errors with the code
+//   - Message: Default message of errors with the code
+type CodeInfo struct {
+	StatusCode int
+	Message    string
+}
+
+var (
+	codesMu sync.RWMutex
+	codes   = map[string]CodeInfo{
+		CodeInvalidRequest:        {http.StatusBadRequest, "invalid request"},
+		CodeValidationFailed:      {http.StatusBadRequest, "invalid request"},
+		CodeUnauthenticated:       {http.StatusUnauthorized, "authentication required"},
+		CodeMissingToken:          {http.StatusUnauthorized, "missing token in header"},
+		CodeInvalidTokenFormat:    {http.StatusUnauthorized, "token is invalid format"},
+		CodeInvalidTokenSignature: {http.StatusUnauthorized, "token is invalid signature"},
+		CodePermissionDenied:      {http.StatusForbidden, "permission denied"},
+		CodeNotFound:              {http.StatusNotFound, "not found"},
+		CodeConflict:              {http.StatusConflict, "conflict"},
+		CodeRequestTooLarge:       {http.StatusRequestEntityTooLarge, "request body too large"},
+		CodeUnprocessableEntity:   {http.StatusUnprocessableEntity, "unprocessable entity"},
+		CodeTooManyRequests:       {http.StatusTooManyRequests, "too many requests"},
+		CodeInternal:              {http.StatusInternalServerError, "internal server error"},
+		CodeServiceUnavailable:    {http.StatusServiceUnavailable, "service unavailable"},
+		CodeTimeout:               {http.StatusGatewayTimeout, "request timed out"},
+	}
+	// statusCodes are the codes given to errors created by status code only.
+	statusCodes = map[int]string{
+		http.StatusBadRequest:            CodeInvalidRequest,
+		http.StatusUnauthorized:          CodeUnauthenticated,
+		http.StatusForbidden:             CodePermissionDenied,
+		http.StatusNotFound:              CodeNotFound,
+		http.StatusConflict:              CodeConflict,
+		http.StatusRequestEntityTooLarge: CodeRequestTooLarge,
+		http.StatusUnprocessableEntity:   CodeUnprocessableEntity,
+		http.StatusTooManyRequests:       CodeTooManyRequests,
+		http.StatusInternalServerError:   CodeInternal,
+		http.StatusServiceUnavailable:    CodeServiceUnavailable,
+		http.StatusGatewayTimeout:        CodeTimeout,
+	}
+)
+
+// RegisterCode registers an error code of the service, so NewError can create
+// errors with it and catalogs can translate its message. Registering a code
+// again replaces it.
+//
+// Parameters:
+//   - code: Stable identifier, e.g. "order_not_found"
+//   - statusCode: HTTP status code of errors with the code
+//   - message: Default message of errors with the code
+//
+// Example:
+//
+//	resp.RegisterCode("order_not_found", http.StatusNotFound, "order not found")
+func RegisterCode(code string, statusCode int, message string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	codes[code] = CodeInfo{StatusCode: statusCode, Message: message}
+}
+
+// LookupCode returns the registered information of code.
+//
+// Parameters:
+//   - code: The error code
+//
+// Returns:
+//   - CodeInfo: The registered status code and message
+//   - bool: Whether the code is registered
+func LookupCode(code string) (CodeInfo, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	info, ok := codes[code]
+	return info, ok
+}
+
+// NewError creates an error response with a registered code. Unregistered
+// codes produce a 500, so typos surface in tests rather than as odd statuses.
+//
+// Parameters:
+//   - code: The registered error code
+//   - root: The root error, logged but never sent to clients (can be nil)
+//
+// Returns:
+//   - *ErrorResp: The error response
+//
+// Example:
+//
+//	return nil, resp.NewError("order_not_found", err).
+//	    WithDetails(resp.ErrorDetail{Type: resp.DetailResource, Reason: "order", Metadata: map[string]string{"id": id}})
+func NewError(code string, root error) *ErrorResp {
+	info, ok := LookupCode(code)
+	if !ok {
+		if root == nil {
+			root = fmt.Errorf("unregistered error code %s", code)
+		}
+		code = CodeInternal
+		info, _ = LookupCode(code)
+	}
+
+	e := NewErrorResp(info.StatusCode, root, info.Message)
+	e.Code = code
+	return e
+}
+
+// codeOfStatus returns the code of errors created by status code only.
+func codeOfStatus(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeInvalidRequest
+}
@@ -1,43 +1,141 @@
 package resp
 
-import "net/http"
-
+// ErrInternalServer creates a 500 error response. The message is generic; err
+// is only logged, so internals never reach clients.
+//
+// Parameters:
+//   - err: The internal error
+//
+// Returns:
+//   - *ErrorResp: The error response
 func ErrInternalServer(err error) *ErrorResp {
-	return NewErrorResp(
-		http.StatusInternalServerError,
-		err,
-		err.Error(),
-	)
+	return NewError(CodeInternal, err)
 }
 
+// ErrInvalidRequest creates a 400 error response whose message describes err.
+//
+// Parameters:
+//   - err: Error describing what is wrong with the request
+//
+// Returns:
+//   - *ErrorResp: The error response
 func ErrInvalidRequest(err error) *ErrorResp {
-	return NewErrorResp(
-		http.StatusBadRequest,
-		err,
-		err.Error(),
-	)
+	return NewError(CodeInvalidRequest, err).WithMessage(err.Error())
+}
+
+// ErrValidation creates a 400 error response listing the fields that failed
+// validation as field violation Details.
+//
+// Parameters:
+//   - err: The validation error
+//   - violations: The invalid fields, of type DetailFieldViolation
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrValidation(err error, violations []ErrorDetail) *ErrorResp {
+	return NewError(CodeValidationFailed, err).WithDetails(violations...)
+}
+
+func ErrForbidden(err error) *ErrorResp {
+	return NewError(CodePermissionDenied, err)
 }
 
 func ErrMissingTokenInHeader(err error) *ErrorResp {
-	return NewErrorResp(
-		http.StatusUnauthorized,
-		err,
-		"missing token in header",
-	)
+	return NewError(CodeMissingToken, err)
 }
 
 func ErrInvalidTokenFormat(err error) *ErrorResp {
-	return NewErrorResp(
-		http.StatusUnauthorized,
-		err,
-		"token is invalid format",
-	)
+	return NewError(CodeInvalidTokenFormat, err)
 }
 
 func ErrInvalidTokenSignature(err error) *ErrorResp {
-	return NewErrorResp(
-		http.StatusUnauthorized,
-		err,
-		"token is invalid signature",
-	)
+	return NewError(CodeInvalidTokenSignature, err)
+}
+
+// ErrUnauthenticated creates a 401 error response for requests without a valid identity.
+//
+// Parameters:
+//   - err: The authentication error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrUnauthenticated(err error) *ErrorResp {
+	return NewError(CodeUnauthenticated, err)
+}
+
+// ErrNotFound creates a 404 error response.
+//
+// Parameters:
+//   - err: The lookup error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrNotFound(err error) *ErrorResp {
+	return NewError(CodeNotFound, err)
+}
+
+// ErrTooManyRequests creates a 429 error response for rate limited requests.
+//
+// Parameters:
+//   - err: The rate limit error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrTooManyRequests(err error) *ErrorResp {
+	return NewError(CodeTooManyRequests, err)
+}
+
+// ErrRequestEntityTooLarge creates a 413 error response for request bodies over the size limit.
+//
+// Parameters:
+//   - err: The size limit error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrRequestEntityTooLarge(err error) *ErrorResp {
+	return NewError(CodeRequestTooLarge, err)
+}
+
+// ErrServiceUnavailable creates a 503 error response for requests shed under load.
+//
+// Parameters:
+//   - err: The load shedding error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrServiceUnavailable(err error) *ErrorResp {
+	return NewError(CodeServiceUnavailable, err)
+}
+
+// ErrGatewayTimeout creates a 504 error response for requests exceeding their deadline.
+//
+// Parameters:
+//   - err: The deadline error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrGatewayTimeout(err error) *ErrorResp {
+	return NewError(CodeTimeout, err)
+}
+
+// ErrConflict creates a 409 error response for requests conflicting with the current state.
+//
+// Parameters:
+//   - err: The conflict error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrConflict(err error) *ErrorResp {
+	return NewError(CodeConflict, err)
+}
+
+// ErrUnprocessableEntity creates a 422 error response for well-formed requests that cannot be processed.
+//
+// Parameters:
+//   - err: The processing error
+//
+// Returns:
+//   - *ErrorResp: The error response
+func ErrUnprocessableEntity(err error) *ErrorResp {
+	return NewError(CodeUnprocessableEntity, err)
 }
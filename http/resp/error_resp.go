@@ -2,17 +2,43 @@ package resp
 
 import (
 	"errors"
+
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/gin-gonic/gin"
+)
+
+// Detail types of ErrorDetail
+const (
+	DetailFieldViolation = "field_violation" // A request field failed validation
+	DetailResource       = "resource"        // The resource the error is about
+	DetailQuota          = "quota"           // A limit the request exceeded
 )
 
+// ErrorResp is the error envelope written by ResponseError. Only StatusCode,
+// Code, Message, Details and RequestId reach clients; RootErr and Log
+// keep the internal cause for logs.
 type ErrorResp struct {
-	StatusCode int    `json:"status_code"`
-	Message    string `json:"message"`
-	Log        string `json:"-"`
-	RootErr    error  `json:"-"`
+	StatusCode int           `json:"status_code"`
+	Code       string        `json:"code,omitempty"` // Stable machine-readable error code, see RegisterCode
+	Message    string        `json:"message"`
+	Details    []ErrorDetail `json:"details,omitempty"`    // Structured details, e.g. field violations
+	RequestId  string        `json:"request_id,omitempty"` // Request ID, set by ResponseError
+	Log        string        `json:"-"`
+	RootErr    error         `json:"-"`
+}
+
+// ErrorDetail is one structured detail of an error response.
+type ErrorDetail struct {
+	Type     string            `json:"type"`               // Kind of detail, e.g. DetailFieldViolation
+	Field    string            `json:"field,omitempty"`    // Request field the detail is about
+	Reason   string            `json:"reason,omitempty"`   // Machine-readable reason, e.g. the failed rule "required"
+	Message  string            `json:"message,omitempty"`  // Human readable description
+	Metadata map[string]string `json:"metadata,omitempty"` // Additional values, e.g. {"param": "3"}
 }
 
 // NewErrorResp creates a new custom error response.
 // It constructs an ErrorResp with a status code, message, and optional root error.
+// The code is derived from the status code; use NewError for registered codes.
 //
 // Parameters:
 //   - statusCode: The HTTP status code for the error response
@@ -38,6 +64,7 @@ func NewErrorResp(statusCode int, root error, msg string) *ErrorResp {
 	if root != nil {
 		return &ErrorResp{
 			StatusCode: statusCode,
+			Code:       codeOfStatus(statusCode),
 			RootErr:    root,
 			Message:    msg,
 			Log:        root.Error(),
@@ -46,12 +73,47 @@ func NewErrorResp(statusCode int, root error, msg string) *ErrorResp {
 
 	return &ErrorResp{
 		StatusCode: statusCode,
+		Code:       codeOfStatus(statusCode),
 		RootErr:    errors.New(msg),
 		Message:    msg,
 		Log:        msg,
 	}
 }
 
+// WithMessage replaces the message of the error response.
+//
+// Parameters:
+//   - msg: The message sent to clients
+//
+// Returns:
+//   - *ErrorResp: The error response, for chaining
+//
+// Examples:
+//
+//	err := resp.NewError(resp.CodeConflict, err).WithMessage("order was already paid")
+func (e *ErrorResp) WithMessage(msg string) *ErrorResp {
+	e.Message = msg
+	return e
+}
+
+// WithDetails appends details to the error response.
+//
+// Parameters:
+//   - details: The details
+//
+// Returns:
+//   - *ErrorResp: The error response, for chaining
+//
+// Examples:
+//
+//	err := resp.ErrTooManyRequests(err).WithDetails(resp.ErrorDetail{
+//	    Type: resp.DetailQuota, Reason: "orders_per_minute", Metadata: map[string]string{"limit": "10"},
+//	})
+func (e *ErrorResp) WithDetails(details ...ErrorDetail) *ErrorResp {
+	e.Details = append(e.Details, details...)
+	return e
+}
+
 // RootError returns the underlying root cause of the error chain.
 // It traverses the error chain to find the original error.
 //
@@ -90,3 +152,58 @@ func (e *ErrorResp) RootError() error {
 func (e *ErrorResp) Error() string {
 	return e.RootError().Error()
 }
+
+// ResponseError writes an error response. The request ID is added, the default
+// message of the code is translated by the catalog matching Accept-Language
+// (messages set with WithMessage are kept), and the error is
+// written as RFC 7807 problem details when configured by SetProblemDetails or
+// requested by the client. err itself is left unchanged.
+//
+// Parameters:
+//   - ctx: The Gin context for the HTTP request
+//   - err: The error response to write
+//
+// Examples:
+//
+//	resp.ResponseError(ctx, resp.NewError("order_not_found", err))
+//	// {"status_code":404,"code":"order_not_found","message":"order not found","request_id":"..."}
+func ResponseError(ctx *gin.Context, err *ErrorResp) {
+	out := *err
+	out.RequestId = metadata.GetRequestId(ctx.Request.Context())
+	if info, ok := LookupCode(out.Code); ok && out.Message == info.Message {
+		if message, lang, ok := translate(ctx.GetHeader("Accept-Language"), out.Code); ok {
+			out.Message = message
+			ctx.Header("Content-Language", lang)
+		}
+	}
+
+	var cfg ProblemConfig
+	if c := problemConfig.Load(); c != nil {
+		cfg = *c
+	}
+	if wantsProblem(ctx, cfg) {
+		ctx.Header("Content-Type", ProblemContentType)
+		ctx.JSON(out.StatusCode, newProblem(ctx, &out, cfg))
+		return
+	}
+	ctx.JSON(out.StatusCode, &out)
+}
+
+// AbortWithError records err on the context, aborts the remaining handlers and
+// writes it with ResponseError.
+//
+// Parameters:
+//   - ctx: The Gin context for the HTTP request
+//   - err: The error response to write
+//
+// Examples:
+//
+//	if !allowed {
+//	    resp.AbortWithError(ctx, resp.ErrForbidden(fmt.Errorf("missing permission %s", permission)))
+//	    return
+//	}
+func AbortWithError(ctx *gin.Context, err *ErrorResp) {
+	_ = ctx.Error(err)
+	ctx.Abort()
+	ResponseError(ctx, err)
+}
@@ -0,0 +1,157 @@
+package resp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/metadata"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewError(t *testing.T) {
+	RegisterCode("order_not_found", http.StatusNotFound, "order not found")
+
+	tests := []struct {
+		name        string
+		code        string
+		root        error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+		wantLog     string
+	}{
+		{
+			name:        "registered code",
+			code:        "order_not_found",
+			root:        errors.New("record not found"),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    "order_not_found",
+			wantMessage: "order not found",
+			wantLog:     "record not found",
+		},
+		{
+			name:        "unregistered code",
+			code:        "order_not_fuond",
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    CodeInternal,
+			wantMessage: "internal server error",
+			wantLog:     "unregistered error code order_not_fuond",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewError(tt.code, tt.root)
+
+			assert.Equal(t, tt.wantStatus, err.StatusCode)
+			assert.Equal(t, tt.wantCode, err.Code)
+			assert.Equal(t, tt.wantMessage, err.Message)
+			assert.Equal(t, tt.wantLog, err.Log)
+		})
+	}
+}
+
+func TestCommonErrors(t *testing.T) {
+	internal := ErrInternalServer(errors.New("pq: connection refused"))
+	assert.Equal(t, "internal server error", internal.Message)
+	assert.Equal(t, "pq: connection refused", internal.Error())
+
+	violations := []ErrorDetail{{
+		Type: DetailFieldViolation, Field: "name", Reason: "min", Message: "name must be at least 3",
+		Metadata: map[string]string{"param": "3"},
+	}}
+	validation := ErrValidation(errors.New("invalid"), violations)
+	assert.Equal(t, CodeValidationFailed, validation.Code)
+	assert.Equal(t, violations, validation.Details)
+
+	assert.Equal(t, CodeNotFound, NewErrorResp(http.StatusNotFound, nil, "api not found").Code)
+}
+
+func TestResponseError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	RegisterCode("order_paid", http.StatusConflict, "order was already paid")
+	require.NoError(t, RegisterCatalog("vi", map[string]string{"order_paid": "đơn hàng đã được thanh toán"}))
+	require.Error(t, RegisterCatalog("not a language!", nil))
+
+	tests := []struct {
+		name            string
+		header          map[string]string
+		problem         *ProblemConfig
+		wantContentType string
+		wantLanguage    string
+		wantBody        string
+	}{
+		{
+			name:            "json",
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"status_code":409,"code":"order_paid","message":"order was already paid","request_id":"req-1"}`,
+		},
+		{
+			name:            "translated",
+			header:          map[string]string{"Accept-Language": "vi-VN, en;q=0.8"},
+			wantContentType: "application/json; charset=utf-8",
+			wantLanguage:    "vi",
+			wantBody:        `{"status_code":409,"code":"order_paid","message":"đơn hàng đã được thanh toán","request_id":"req-1"}`,
+		},
+		{
+			name:            "untranslated language",
+			header:          map[string]string{"Accept-Language": "fr"},
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"status_code":409,"code":"order_paid","message":"order was already paid","request_id":"req-1"}`,
+		},
+		{
+			name:            "problem details requested",
+			header:          map[string]string{"Accept": ProblemContentType},
+			wantContentType: ProblemContentType,
+			wantBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"order was already paid",
+				"instance":"/orders/1/pay","code":"order_paid","request_id":"req-1"}`,
+		},
+		{
+			name:            "problem details always",
+			problem:         &ProblemConfig{Always: true, TypeBase: "https://errors.example.com/"},
+			wantContentType: ProblemContentType,
+			wantBody: `{"type":"https://errors.example.com/order_paid","title":"Conflict","status":409,
+				"detail":"order was already paid","instance":"/orders/1/pay","code":"order_paid","request_id":"req-1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.problem != nil {
+				SetProblemDetails(*tt.problem)
+				t.Cleanup(func() { SetProblemDetails(ProblemConfig{}) })
+			}
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/orders/1/pay", nil)
+			ctx.Request = ctx.Request.WithContext(metadata.SetRequestId(ctx.Request.Context(), "req-1"))
+			for key, value := range tt.header {
+				ctx.Request.Header.Set(key, value)
+			}
+
+			err := NewError("order_paid", errors.New("payment exists"))
+			AbortWithError(ctx, err)
+
+			assert.True(t, ctx.IsAborted())
+			assert.Equal(t, http.StatusConflict, recorder.Code)
+			assert.Equal(t, tt.wantContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantLanguage, recorder.Header().Get("Content-Language"))
+			assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+			assert.Empty(t, err.RequestId)
+		})
+	}
+	// Messages set with WithMessage are not replaced by the catalog
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/orders/1/pay", nil)
+	ctx.Request.Header.Set("Accept-Language", "vi")
+	ResponseError(ctx, NewError("order_paid", nil).WithMessage("order 1 was already paid"))
+
+	assert.Empty(t, recorder.Header().Get("Content-Language"))
+	assert.JSONEq(t, `{"status_code":409,"code":"order_paid","message":"order 1 was already paid"}`, recorder.Body.String())
+}
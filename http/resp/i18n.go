@@ -0,0 +1,86 @@
+package resp
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	catalogsMu sync.RWMutex
+	// defaultLanguage is the language of registered code messages, which are never translated.
+	defaultLanguage = language.English
+	catalogs        = map[language.Tag]map[string]string{}
+	supported       = []language.Tag{defaultLanguage}
+	matcher         = language.NewMatcher(supported)
+)
+
+// RegisterCatalog registers translated messages of error codes for a language.
+// ResponseError picks the catalog best matching the Accept-Language header and
+// replaces the message of errors whose code it translates, unless the message
+// was changed from the default of the code, e.g. with WithMessage. Registering
+// a language again adds to its catalog.
+//
+// Parameters:
+//   - lang: BCP 47 language tag, e.g. "vi" or "pt-BR"
+//   - messages: Messages by error code
+//
+// Returns:
+//   - error: Error if lang is not a valid language tag
+//
+// Example:
+//
+//	err := resp.RegisterCatalog("vi", map[string]string{
+//	    resp.CodePermissionDenied: "không có quyền truy cập",
+//	    "order_not_found":         "không tìm thấy đơn hàng",
+//	})
+func RegisterCatalog(lang string, messages map[string]string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return fmt.Errorf("invalid catalog language %s: %w", lang, err)
+	}
+
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	catalog, ok := catalogs[tag]
+	if !ok {
+		catalog = map[string]string{}
+		catalogs[tag] = catalog
+		if tag != defaultLanguage {
+			supported = append(supported, tag)
+			matcher = language.NewMatcher(supported)
+		}
+	}
+	for code, message := range messages {
+		catalog[code] = message
+	}
+	return nil
+}
+
+// translate returns the message of code in the language best matching an
+// Accept-Language header, and the tag of that language.
+func translate(acceptLanguage, code string) (string, string, bool) {
+	if acceptLanguage == "" || code == "" {
+		return "", "", false
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return "", "", false
+	}
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return "", "", false
+	}
+	tag := supported[index]
+	message, ok := catalogs[tag][code]
+	if !ok {
+		return "", "", false
+	}
+	return message, tag.String(), true
+}
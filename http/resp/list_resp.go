@@ -0,0 +1,123 @@
+package resp
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/anthanhphan/saturday/pagination"
+)
+
+// List is the metadata of list responses: a page of items and how to reach
+// the pages around it.
+type List[T any] struct {
+	Items []T                 `json:"items"`           // Items of the page
+	Page  pagination.PageInfo `json:"page"`            // Description of the page
+	Links *Links              `json:"links,omitempty"` // Links to the pages around, set by ResponseSuccess
+}
+
+// Links are the URLs of the pages around a page, relative to the host.
+type Links struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// NewList creates the metadata of a list response. ResponseSuccess adds the
+// links built from the request URL.
+//
+// Parameters:
+//   - items: Items of the page
+//   - page: Description of the page
+//
+// Returns:
+//   - List[T]: The list
+//
+// Examples:
+//
+//	func (h *OrderHandler) List(ctx *gin.Context) {
+//	    params, err := pagination.Parse(ctx.Request.URL.Query())
+//	    if err != nil {
+//	        resp.AbortWithError(ctx, resp.MapError(ctx.Request.Context(), err))
+//	        return
+//	    }
+//	    var orders []Order
+//	    page, err := h.db.FindPage(ctx.Request.Context(), nil, &orders, params, orderPageConfig)
+//	    if err != nil {
+//	        resp.AbortWithError(ctx, resp.MapError(ctx.Request.Context(), err))
+//	        return
+//	    }
+//	    resp.ResponseSuccess(ctx, resp.NewSuccessResp("success", resp.NewList(orders, page)))
+//	}
+//	// {"status_code":200,"message":"success","metadata":{"items":[...],
+//	//  "page":{"limit":20,"has_next":true,"has_prev":false,"next_cursor":"eyJ2Ij..."},
+//	//  "links":{"self":"/orders?limit=20","first":"/orders?limit=20","next":"/orders?cursor=eyJ2Ij...&limit=20"}}}
+func NewList[T any](items []T, page pagination.PageInfo) List[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return List[T]{Items: items, Page: page}
+}
+
+// NewListResp creates a success response holding a list.
+//
+// Parameters:
+//   - msg: A string message describing the success response
+//   - items: Items of the page
+//   - page: Description of the page
+//
+// Returns:
+//   - *SuccessResp: The success response
+//
+// Examples:
+//
+//	resp.ResponseSuccess(ctx, resp.NewListResp("success", orders, page))
+func NewListResp[T any](msg string, items []T, page pagination.PageInfo) *SuccessResp {
+	return NewSuccessResp(msg, NewList(items, page))
+}
+
+// linker is implemented by metadata whose links come from the request URL.
+type linker interface {
+	withLinks(u *url.URL) any
+}
+
+// withLinks returns the list with links built from the request URL, keeping its
+// limit, sort and filters.
+func (l List[T]) withLinks(u *url.URL) any {
+	if l.Links != nil {
+		return l
+	}
+
+	link := func(set func(url.Values)) string {
+		query := u.Query()
+		query.Del("page")
+		query.Del("cursor")
+		set(query)
+		if encoded := query.Encode(); encoded != "" {
+			return u.Path + "?" + encoded
+		}
+		return u.Path
+	}
+
+	l.Links = &Links{Self: u.RequestURI()}
+	if l.Page.Page > 0 {
+		// Offset pagination
+		l.Links.First = link(func(q url.Values) { q.Set("page", "1") })
+		if l.Page.HasNext {
+			l.Links.Next = link(func(q url.Values) { q.Set("page", strconv.Itoa(l.Page.Page+1)) })
+		}
+		if l.Page.HasPrev {
+			l.Links.Prev = link(func(q url.Values) { q.Set("page", strconv.Itoa(l.Page.Page-1)) })
+		}
+		return l
+	}
+
+	l.Links.First = link(func(url.Values) {})
+	if l.Page.NextCursor != "" {
+		l.Links.Next = link(func(q url.Values) { q.Set("cursor", l.Page.NextCursor) })
+	}
+	if l.Page.PrevCursor != "" {
+		l.Links.Prev = link(func(q url.Values) { q.Set("cursor", l.Page.PrevCursor) })
+	}
+	return l
+}
@@ -0,0 +1,59 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthanhphan/saturday/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseSuccessList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		target   string
+		items    []string
+		page     pagination.PageInfo
+		wantBody string
+	}{
+		{
+			name:   "cursor",
+			target: "/orders?cursor=prev&limit=2&sort=-created_at",
+			items:  []string{"a", "b"},
+			page:   pagination.PageInfo{Limit: 2, HasNext: true, HasPrev: true, NextCursor: "n1", PrevCursor: "p1"},
+			wantBody: `{"status_code":200,"message":"success","metadata":{
+				"items":["a","b"],
+				"page":{"limit":2,"has_next":true,"has_prev":true,"next_cursor":"n1","prev_cursor":"p1"},
+				"links":{
+					"self":"/orders?cursor=prev&limit=2&sort=-created_at",
+					"first":"/orders?limit=2&sort=-created_at",
+					"next":"/orders?cursor=n1&limit=2&sort=-created_at",
+					"prev":"/orders?cursor=p1&limit=2&sort=-created_at"}}}`,
+		},
+		{
+			name:   "offset",
+			target: "/orders?page=1",
+			page:   pagination.PageInfo{Limit: 20, Page: 1, Total: new(int64)},
+			wantBody: `{"status_code":200,"message":"success","metadata":{
+				"items":[],
+				"page":{"limit":20,"page":1,"total":0,"has_next":false,"has_prev":false},
+				"links":{"self":"/orders?page=1","first":"/orders?page=1"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			ResponseSuccess(ctx, NewListResp("success", tt.items, tt.page))
+
+			assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/anthanhphan/saturday/pagination"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ErrorMapper converts an error into an error response. It returns nil for
+// errors it does not handle, so the next mapper is tried.
+//
+// Parameters:
+//   - ctx: Context of the request the error belongs to
+//   - err: The error
+//
+// Returns:
+//   - *ErrorResp: The error response, or nil
+type ErrorMapper func(ctx context.Context, err error) *ErrorResp
+
+var (
+	mappersMu sync.RWMutex
+	mappers   []ErrorMapper
+	// defaultMappers run after the registered ones, so those can override them.
+	defaultMappers = []ErrorMapper{
+		mapRecordNotFound,
+		mapDeadlineExceeded,
+		mapMaxBytes,
+		mapValidation,
+		mapInvalidParams,
+	}
+)
+
+// RegisterErrorMapper registers a mapper used by MapError. Mappers registered
+// later run first; the built-in mappers of record not found, deadline, body
+// size, validation and pagination parameter errors run last.
+//
+// Parameters:
+//   - mapper: The mapper
+//
+// Example:
+//
+//	resp.RegisterErrorMapper(func(_ context.Context, err error) *resp.ErrorResp {
+//	    var pgErr *pgconn.PgError
+//	    if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+//	        return resp.ErrConflict(err).WithMessage("resource already exists")
+//	    }
+//	    return nil
+//	})
+func RegisterErrorMapper(mapper ErrorMapper) {
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	mappers = append(mappers, mapper)
+}
+
+// RegisterSentinel maps errors matching target by errors.Is to the registered code.
+//
+// Parameters:
+//   - target: The sentinel error
+//   - code: The registered error code, see RegisterCode
+//
+// Example:
+//
+//	var ErrOrderPaid = errors.New("order already paid")
+//
+//	resp.RegisterCode("order_paid", http.StatusConflict, "order was already paid")
+//	resp.RegisterSentinel(ErrOrderPaid, "order_paid")
+func RegisterSentinel(target error, code string) {
+	RegisterErrorMapper(func(_ context.Context, err error) *ErrorResp {
+		if errors.Is(err, target) {
+			return NewError(code, err)
+		}
+		return nil
+	})
+}
+
+// MapError converts err into the error response written to clients. An
+// *ErrorResp in the chain of err is returned as is; otherwise the registered
+// mappers, then the built-in ones, are tried in turn. Errors no mapper handles
+// become a 500 whose message hides err.
+//
+// Parameters:
+//   - ctx: Context of the request the error belongs to
+//   - err: The error
+//
+// Returns:
+//   - *ErrorResp: The error response
+//
+// Example:
+//
+//	if err := orderService.Pay(ctx.Request.Context(), id); err != nil {
+//	    resp.AbortWithError(ctx, resp.MapError(ctx.Request.Context(), err))
+//	    return
+//	}
+func MapError(ctx context.Context, err error) *ErrorResp {
+	var errResp *ErrorResp
+	if errors.As(err, &errResp) {
+		return errResp
+	}
+
+	mappersMu.RLock()
+	registered := mappers
+	mappersMu.RUnlock()
+
+	for i := len(registered) - 1; i >= 0; i-- {
+		if errResp := registered[i](ctx, err); errResp != nil {
+			return errResp
+		}
+	}
+	for _, mapper := range defaultMappers {
+		if errResp := mapper(ctx, err); errResp != nil {
+			return errResp
+		}
+	}
+	return ErrInternalServer(err)
+}
+
+func mapRecordNotFound(_ context.Context, err error) *ErrorResp {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound(err)
+	}
+	return nil
+}
+
+// mapDeadlineExceeded maps deadline errors of the request itself, e.g. set by
+// middlewares.Timeout. Deadlines of outgoing calls the request outlived stay 500s.
+func mapDeadlineExceeded(ctx context.Context, err error) *ErrorResp {
+	if errors.Is(err, context.DeadlineExceeded) && ctx != nil && ctx.Err() != nil {
+		return ErrGatewayTimeout(err)
+	}
+	return nil
+}
+
+func mapMaxBytes(_ context.Context, err error) *ErrorResp {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return ErrRequestEntityTooLarge(err)
+	}
+	return nil
+}
+
+func mapInvalidParams(_ context.Context, err error) *ErrorResp {
+	if errors.Is(err, pagination.ErrInvalidParams) {
+		return ErrInvalidRequest(err)
+	}
+	return nil
+}
+
+// mapValidation maps validator failures to a validation error listing the
+// fields, named by the tag name function of the validator.
+func mapValidation(_ context.Context, err error) *ErrorResp {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return nil
+	}
+
+	violations := make([]ErrorDetail, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		// Namespace is "Req.items[0].sku"; drop the root struct name.
+		_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+		violation := ErrorDetail{
+			Type:    DetailFieldViolation,
+			Field:   field,
+			Reason:  fieldErr.Tag(),
+			Message: ruleMessage(fieldErr.Tag(), fieldErr.Param()),
+		}
+		if param := fieldErr.Param(); param != "" {
+			violation.Metadata = map[string]string{"param": param}
+		}
+		violations = append(violations, violation)
+	}
+	return ErrValidation(err, violations)
+}
+
+// ruleMessage describes a failed validation rule.
+func ruleMessage(tag, param string) string {
+	switch tag {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "len":
+		return "must have length " + param
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "url", "uri":
+		return "must be a valid URL"
+	default:
+		if param == "" {
+			return "failed on " + tag
+		}
+		return fmt.Sprintf("failed on %s=%s", tag, param)
+	}
+}
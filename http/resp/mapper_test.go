@@ -0,0 +1,126 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/anthanhphan/saturday/pagination"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var errOrderLocked = errors.New("order locked")
+
+func TestMapError(t *testing.T) {
+	RegisterCode("order_locked", http.StatusLocked, "order is locked")
+	RegisterSentinel(errOrderLocked, "order_locked")
+	RegisterErrorMapper(func(_ context.Context, err error) *ErrorResp {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrConflict(err).WithMessage("order already exists")
+		}
+		return nil
+	})
+
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	type order struct {
+		Name string `validate:"required"`
+	}
+	validationErr := validator.New().Struct(order{})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "error response",
+			ctx:        context.Background(),
+			err:        fmt.Errorf("wrapped: %w", ErrForbidden(errors.New("denied"))),
+			wantStatus: http.StatusForbidden,
+			wantCode:   CodePermissionDenied,
+		},
+		{
+			name:       "registered sentinel",
+			ctx:        context.Background(),
+			err:        fmt.Errorf("failed to pay order: %w", errOrderLocked),
+			wantStatus: http.StatusLocked,
+			wantCode:   "order_locked",
+		},
+		{
+			name:       "registered mapper",
+			ctx:        context.Background(),
+			err:        gorm.ErrDuplicatedKey,
+			wantStatus: http.StatusConflict,
+			wantCode:   CodeConflict,
+		},
+		{
+			name:       "record not found",
+			ctx:        context.Background(),
+			err:        fmt.Errorf("failed to load order: %w", gorm.ErrRecordNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeNotFound,
+		},
+		{
+			name:       "request deadline",
+			ctx:        expired,
+			err:        fmt.Errorf("failed to load order: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   CodeTimeout,
+		},
+		{
+			name:       "deadline of an outgoing call",
+			ctx:        context.Background(),
+			err:        context.DeadlineExceeded,
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+		},
+		{
+			name:       "body too large",
+			ctx:        context.Background(),
+			err:        fmt.Errorf("invalid request body: %w", &http.MaxBytesError{Limit: 10}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   CodeRequestTooLarge,
+		},
+		{
+			name:       "validation",
+			ctx:        context.Background(),
+			err:        validationErr,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeValidationFailed,
+		},
+		{
+			name:       "pagination parameters",
+			ctx:        context.Background(),
+			err:        fmt.Errorf("%w: unknown sort field password", pagination.ErrInvalidParams),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeInvalidRequest,
+		},
+		{
+			name:       "unmapped",
+			ctx:        context.Background(),
+			err:        errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errResp := MapError(tt.ctx, tt.err)
+
+			assert.Equal(t, tt.wantStatus, errResp.StatusCode)
+			assert.Equal(t, tt.wantCode, errResp.Code)
+		})
+	}
+
+	errResp := MapError(context.Background(), validationErr)
+	assert.Equal(t, []ErrorDetail{{Type: DetailFieldViolation, Field: "Name", Reason: "required", Message: "is required"}}, errResp.Details)
+}
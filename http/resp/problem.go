@@ -0,0 +1,74 @@
+package resp
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the RFC 7807 representation of an ErrorResp.
+type Problem struct {
+	Type      string        `json:"type"`                 // URI identifying the error code, "about:blank" without a TypeBase
+	Title     string        `json:"title"`                // Status text, e.g. "Not Found"
+	Status    int           `json:"status"`               // HTTP status code
+	Detail    string        `json:"detail,omitempty"`     // Message of the error
+	Instance  string        `json:"instance,omitempty"`   // Path of the request
+	Code      string        `json:"code,omitempty"`       // Stable machine-readable error code
+	RequestId string        `json:"request_id,omitempty"` // Request ID
+	Details   []ErrorDetail `json:"details,omitempty"`    // Structured details
+}
+
+// ProblemConfig configures problem details output.
+//
+// Fields:
+//   - Always: Write problem details for every error, not only to clients accepting application/problem+json
+//   - TypeBase: URI prefix of the type member, followed by the error code, e.g. "https://errors.example.com/"
+type ProblemConfig struct {
+	Always   bool
+	TypeBase string
+}
+
+var problemConfig atomic.Pointer[ProblemConfig]
+
+// SetProblemDetails configures when ResponseError writes RFC 7807 problem
+// details. Without it, problem details are only written to clients sending
+// Accept: application/problem+json, with an "about:blank" type.
+//
+// Parameters:
+//   - cfg: The configuration
+//
+// Example:
+//
+//	resp.SetProblemDetails(resp.ProblemConfig{Always: true, TypeBase: "https://errors.example.com/"})
+func SetProblemDetails(cfg ProblemConfig) {
+	problemConfig.Store(&cfg)
+}
+
+// wantsProblem reports whether the error of the request is written as problem details.
+func wantsProblem(ctx *gin.Context, cfg ProblemConfig) bool {
+	return cfg.Always || strings.Contains(ctx.GetHeader("Accept"), ProblemContentType)
+}
+
+// newProblem converts an error response into problem details.
+func newProblem(ctx *gin.Context, err *ErrorResp, cfg ProblemConfig) Problem {
+	problemType := "about:blank"
+	if cfg.TypeBase != "" && err.Code != "" {
+		problemType = cfg.TypeBase + err.Code
+	}
+
+	return Problem{
+		Type:      problemType,
+		Title:     http.StatusText(err.StatusCode),
+		Status:    err.StatusCode,
+		Detail:    err.Message,
+		Instance:  ctx.Request.URL.Path,
+		Code:      err.Code,
+		RequestId: err.RequestId,
+		Details:   err.Details,
+	}
+}
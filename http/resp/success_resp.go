@@ -42,6 +42,7 @@ func NewSuccessResp(msg string, data interface{}) *SuccessResp {
 }
 
 // ResponseSuccess writes a success response to the HTTP response writer using Gin's JSON serialization.
+// List metadata gets the links to the pages around it, built from the request URL.
 //
 // Parameters:
 //   - ctx: The Gin context for the HTTP request
@@ -54,5 +55,10 @@ func NewSuccessResp(msg string, data interface{}) *SuccessResp {
 //	ResponseSuccess(ctx, resp)
 //	// Writes a JSON response with status code 200 and message "operation successful"
 func ResponseSuccess(ctx *gin.Context, response *SuccessResp) {
+	if list, ok := response.Metadata.(linker); ok && ctx.Request != nil {
+		out := *response
+		out.Metadata = list.withLinks(ctx.Request.URL)
+		response = &out
+	}
 	ctx.JSON(response.StatusCode, response)
 }
@@ -0,0 +1,34 @@
+package route
+
+// Doc describes a route for the generated OpenAPI document. Type fields hold a
+// value of the described type, e.g. CreateUserRequest{}; only the type is used.
+//
+// Fields:
+//   - Summary: Short summary of the operation
+//   - Description: Longer description of the operation
+//   - Tags: Tags grouping the operation in the docs UI
+//   - OperationID: Unique operation identifier (derived from method and path when empty)
+//   - Body: Type of the JSON request body
+//   - Query: Struct whose `form` tagged fields are query parameters
+//   - Params: Struct whose `uri` tagged fields are path parameters
+//   - Headers: Struct whose `header` tagged fields are header parameters
+//   - Response: Type of the metadata of the resp.SuccessResp body
+//   - SuccessStatus: Status code of the success response (defaults to 200)
+//   - Errors: Error responses by status code; a nil type documents resp.ErrorResp
+//   - Deprecated: Marks the operation as deprecated
+//   - Hidden: Leaves the route out of the document
+type Doc struct {
+	Summary       string
+	Description   string
+	Tags          []string
+	OperationID   string
+	Body          any
+	Query         any
+	Params        any
+	Headers       any
+	Response      any
+	SuccessStatus int
+	Errors        map[int]any
+	Deprecated    bool
+	Hidden        bool
+}
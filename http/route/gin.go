@@ -188,7 +188,7 @@ func AddHealthCheckRoute() GinOption {
 func AddRouteNotFoundHandler() GinOption {
 	return func(g *gin.Engine) {
 		g.NoRoute(func(g *gin.Context) {
-			panic(resp.NewErrorResp(http.StatusNotFound, nil, "api not found"))
+			resp.AbortWithError(g, resp.NewErrorResp(http.StatusNotFound, nil, "api not found"))
 		})
 	}
 }
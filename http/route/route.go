@@ -9,6 +9,8 @@ type GroupRoute struct {
 	Prefix      string
 	Middlewares []func(*gin.Context)
 	Routes      []Route
+	Tags        []string // OpenAPI tags added to every route of the group
+	Permissions []string // Permissions required for every route of the group, enforced by authz.Protect
 }
 
 type Route struct {
@@ -16,6 +18,8 @@ type Route struct {
 	Method      method.Method
 	Handler     func(*gin.Context)
 	Middlewares []func(*gin.Context)
+	Doc         *Doc     // Optional OpenAPI metadata
+	Permissions []string // Permissions required to call the route, enforced by authz.Protect
 }
 
 // CombineHandler merges route middlewares and the main handler into a single slice.
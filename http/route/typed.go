@@ -0,0 +1,313 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/anthanhphan/saturday/http/constant/method"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+var (
+	validatorOnce sync.Once
+	validatorInst *validator.Validate
+)
+
+// bindTags lists the struct tags the typed adapter binds from, in the order
+// field names are reported in validation errors.
+var bindTags = []string{"json", "uri", "form", "header"}
+
+// Typed adapts a typed function into a Gin handler. The request is bound from
+// the path (`uri` tags), query (`form` tags), headers (`header` tags) and JSON
+// body (`json` tags), then validated against its `validate` tags. The result is
+// written as a resp.SuccessResp, unless fn returns a *resp.SuccessResp itself.
+//
+// Validation failures produce a 400 resp.ErrorResp with field-level details,
+// and bodies over the limit of middlewares.BodyLimit a 413. Errors returned by
+// fn are converted by resp.MapError: a wrapped *resp.ErrorResp is written
+// as-is, registered and built-in mappings (record not found, expired request
+// deadline, ...) apply next, and other errors are written as a 500.
+//
+// Parameters:
+//   - fn: Function handling the bound request; ctx is the request context
+//
+// Returns:
+//   - func(*gin.Context): Gin handler for Route.Handler
+//
+// Example:
+//
+//	type GetOrderReq struct {
+//	    ID     int64  `uri:"id" validate:"required"`
+//	    Expand string `form:"expand" validate:"omitempty,oneof=items customer"`
+//	    Tenant string `header:"X-Tenant-Id" validate:"required"`
+//	}
+//
+//	route.Route{
+//	    Path:   "/orders/:id",
+//	    Method: method.GET,
+//	    Handler: route.Typed(func(ctx context.Context, req GetOrderReq) (*Order, error) {
+//	        return orderService.Get(ctx, req.Tenant, req.ID)
+//	    }),
+//	}
+func Typed[Req, Res any](fn func(ctx context.Context, req Req) (Res, error)) func(*gin.Context) {
+	log := zap.L().With(zap.String("prefix", "Typed")).Sugar()
+	sources := bindSources(reflect.TypeOf((*Req)(nil)).Elem())
+
+	return func(ctx *gin.Context) {
+		var req Req
+		if err := bind(ctx, &req, sources); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				resp.AbortWithError(ctx, resp.ErrRequestEntityTooLarge(err))
+				return
+			}
+			resp.AbortWithError(ctx, resp.ErrInvalidRequest(err))
+			return
+		}
+
+		if err := validateRequest(&req); err != nil {
+			resp.AbortWithError(ctx, resp.MapError(ctx.Request.Context(), err))
+			return
+		}
+
+		res, err := fn(ctx.Request.Context(), req)
+		if err != nil {
+			errResp := resp.MapError(ctx.Request.Context(), err)
+			if errResp.StatusCode >= http.StatusInternalServerError {
+				log.Error(err)
+			}
+			resp.AbortWithError(ctx, errResp)
+			return
+		}
+
+		if success, ok := any(res).(*resp.SuccessResp); ok {
+			resp.ResponseSuccess(ctx, success)
+			return
+		}
+		resp.ResponseSuccess(ctx, resp.NewSuccessResp("success", res))
+	}
+}
+
+// TypedRoute creates a Route handled by Typed(fn) and documents it for
+// OpenAPI: unset Body, Params, Query, Headers and Response fields of doc are
+// filled from the tags of Req and the type of Res.
+//
+// Parameters:
+//   - m: HTTP method
+//   - path: Route path
+//   - fn: Function handling the bound request
+//   - doc: Optional documentation (summary, tags, errors, ...)
+//
+// Returns:
+//   - Route: The route
+//
+// Example:
+//
+//	route.TypedRoute(method.POST, "/orders", orderHandler.Create, &route.Doc{
+//	    Summary:       "Create an order",
+//	    SuccessStatus: http.StatusCreated,
+//	})
+func TypedRoute[Req, Res any](m method.Method, path string, fn func(ctx context.Context, req Req) (Res, error), doc *Doc) Route {
+	d := Doc{}
+	if doc != nil {
+		d = *doc
+	}
+
+	var (
+		req Req
+		res Res
+	)
+	sources := bindSources(reflect.TypeOf(&req).Elem())
+	if d.Body == nil && sources["json"] {
+		d.Body = req
+	}
+	if d.Params == nil && sources["uri"] {
+		d.Params = req
+	}
+	if d.Query == nil && sources["form"] {
+		d.Query = req
+	}
+	if d.Headers == nil && sources["header"] {
+		d.Headers = req
+	}
+	if _, ok := any(res).(*resp.SuccessResp); !ok && d.Response == nil {
+		d.Response = res
+	}
+
+	return Route{
+		Path:    path,
+		Method:  m,
+		Handler: Typed(fn),
+		Doc:     &d,
+	}
+}
+
+// bindSources reports which of the bind tags are used by the fields of t.
+func bindSources(t reflect.Type) map[string]bool {
+	sources := map[string]bool{}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		sources["json"] = true
+		return sources
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && isBindStruct(field.Type) && fieldBindName(field) == "" {
+			for source := range bindSources(field.Type) {
+				sources[source] = true
+			}
+			continue
+		}
+
+		tagged := false
+		for _, key := range bindTags[1:] {
+			if name := tagName(field.Tag.Get(key)); name != "" && name != "-" {
+				sources[key] = true
+				tagged = true
+			}
+		}
+		if !tagged && tagName(field.Tag.Get("json")) != "-" {
+			sources["json"] = true
+		}
+	}
+	return sources
+}
+
+// bind fills req from the request sources used by its fields. The body is
+// bound first, then path, query and header values are bound into a fresh value
+// and only the fields tagged for their source are copied, so neither a body key
+// nor a parameter named after an untagged field can overwrite another source.
+func bind(ctx *gin.Context, req any, sources map[string]bool) error {
+	if sources["json"] && ctx.Request.Body != nil && ctx.Request.Body != http.NoBody && ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(req); err != nil {
+			return fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+	if sources["uri"] && len(ctx.Params) > 0 {
+		if err := bindTagged(req, "uri", ctx.ShouldBindUri); err != nil {
+			return fmt.Errorf("invalid path parameters: %w", err)
+		}
+	}
+	if sources["form"] && ctx.Request.URL.RawQuery != "" {
+		if err := bindTagged(req, "form", ctx.ShouldBindQuery); err != nil {
+			return fmt.Errorf("invalid query parameters: %w", err)
+		}
+	}
+	if sources["header"] {
+		if err := bindTagged(req, "header", ctx.ShouldBindHeader); err != nil {
+			return fmt.Errorf("invalid headers: %w", err)
+		}
+	}
+	return nil
+}
+
+// bindTagged binds a source into a zero value of the type req points to and
+// copies the fields tagged with key into req. gin maps untagged fields by their
+// Go name, which must not reach req.
+func bindTagged(req any, key string, bindFn func(any) error) error {
+	src := reflect.New(reflect.TypeOf(req).Elem())
+	if err := bindFn(src.Interface()); err != nil {
+		return err
+	}
+	copyTagged(reflect.ValueOf(req).Elem(), src.Elem(), key)
+	return nil
+}
+
+// copyTagged copies the fields of src tagged with key into dst, including those
+// of embedded structs.
+func copyTagged(dst, src reflect.Value, key string) {
+	if src.Kind() == reflect.Pointer {
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst, src = dst.Elem(), src.Elem()
+	}
+	if src.Kind() != reflect.Struct {
+		return
+	}
+
+	t := src.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && isBindStruct(field.Type) && fieldBindName(field) == "" {
+			copyTagged(dst.Field(i), src.Field(i), key)
+			continue
+		}
+		if name := tagName(field.Tag.Get(key)); name != "" && name != "-" {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
+
+// validateRequest validates req against its `validate` tags. Failures are
+// validator.ValidationErrors, which resp.MapError turns into a validation error.
+func validateRequest(req any) error {
+	t := reflect.TypeOf(req)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return getValidator().Struct(req)
+}
+
+// getValidator returns the shared validator that reports fields by the name
+// the client used (json, uri, form or header tag).
+func getValidator() *validator.Validate {
+	validatorOnce.Do(func() {
+		validatorInst = validator.New(validator.WithRequiredStructEnabled())
+		validatorInst.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := fieldBindName(field)
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	})
+	return validatorInst
+}
+
+// fieldBindName returns the first name given to field by a bind tag.
+func fieldBindName(field reflect.StructField) string {
+	for _, key := range bindTags {
+		if name := tagName(field.Tag.Get(key)); name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// isBindStruct reports whether t is a struct whose fields are bound individually.
+func isBindStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// tagName returns the name part of a struct tag value such as "name,omitempty".
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -0,0 +1,188 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthanhphan/saturday/http/constant/method"
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderReq struct {
+	ID     int64    `uri:"id" validate:"required"`
+	Expand string   `form:"expand" validate:"omitempty,oneof=items customer"`
+	Tenant string   `header:"X-Tenant-Id" validate:"required"`
+	Note   string   `json:"note" validate:"max=5"`
+	Items  []string `json:"items" validate:"min=1"`
+}
+
+type orderRes struct {
+	ID     int64  `json:"id"`
+	Tenant string `json:"tenant"`
+	Expand string `json:"expand"`
+	Note   string `json:"note"`
+}
+
+func serve(t *testing.T, r Route, req *http.Request) (int, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := NewGinEngine(AddRoutes([]Route{r}))
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+
+	body := map[string]any{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return recorder.Code, body
+}
+
+func TestTyped(t *testing.T) {
+	handler := func(_ context.Context, req orderReq) (orderRes, error) {
+		if req.ID == 404 {
+			return orderRes{}, resp.NewErrorResp(http.StatusNotFound, nil, "order not found")
+		}
+		if req.ID == 500 {
+			return orderRes{}, errors.New("db down")
+		}
+		return orderRes{ID: req.ID, Tenant: req.Tenant, Expand: req.Expand, Note: req.Note}, nil
+	}
+	r := Route{Path: "/orders/:id", Method: method.POST, Handler: Typed(handler)}
+
+	tests := []struct {
+		name       string
+		path       string
+		tenant     string
+		headers    map[string]string
+		body       string
+		wantStatus int
+		check      func(t *testing.T, body map[string]any)
+	}{
+		{
+			name:       "success",
+			path:       "/orders/7?expand=items",
+			tenant:     "acme",
+			body:       `{"note":"hi","items":["a"]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "success", body["message"])
+				assert.Equal(t, map[string]any{"id": float64(7), "tenant": "acme", "expand": "items", "note": "hi"}, body["metadata"])
+			},
+		},
+		{
+			name:       "body cannot overwrite path, query and header fields",
+			path:       "/orders/1?expand=items",
+			tenant:     "acme",
+			body:       `{"ID":999,"Expand":"customer","Tenant":"other","items":["a"]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, map[string]any{"id": float64(1), "tenant": "acme", "expand": "items", "note": ""}, body["metadata"])
+			},
+		},
+		{
+			name:       "query and headers cannot overwrite untagged body fields",
+			path:       "/orders/7?expand=items&Note=query",
+			tenant:     "acme",
+			headers:    map[string]string{"Note": "header"},
+			body:       `{"note":"hi","items":["a"]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, map[string]any{"id": float64(7), "tenant": "acme", "expand": "items", "note": "hi"}, body["metadata"])
+			},
+		},
+		{
+			name:       "validation errors",
+			path:       "/orders/7?expand=all",
+			body:       `{"note":"too long","items":[]}`,
+			wantStatus: http.StatusBadRequest,
+			check: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "invalid request", body["message"])
+				assert.ElementsMatch(t, []any{
+					map[string]any{"type": "field_violation", "field": "expand", "reason": "oneof", "message": "must be one of: items, customer", "metadata": map[string]any{"param": "items customer"}},
+					map[string]any{"type": "field_violation", "field": "X-Tenant-Id", "reason": "required", "message": "is required"},
+					map[string]any{"type": "field_violation", "field": "note", "reason": "max", "message": "must be at most 5", "metadata": map[string]any{"param": "5"}},
+					map[string]any{"type": "field_violation", "field": "items", "reason": "min", "message": "must be at least 1", "metadata": map[string]any{"param": "1"}},
+				}, body["details"])
+			},
+		},
+		{
+			name:       "malformed body",
+			path:       "/orders/7",
+			tenant:     "acme",
+			body:       `{"note":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid path parameter",
+			path:       "/orders/abc",
+			tenant:     "acme",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "error response",
+			path:       "/orders/404",
+			tenant:     "acme",
+			body:       `{"items":["a"]}`,
+			wantStatus: http.StatusNotFound,
+			check: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "order not found", body["message"])
+			},
+		},
+		{
+			name:       "internal error",
+			path:       "/orders/500",
+			tenant:     "acme",
+			body:       `{"items":["a"]}`,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-Id", tt.tenant)
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			status, body := serve(t, r, req)
+			assert.Equal(t, tt.wantStatus, status)
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+		})
+	}
+}
+
+func TestTypedSuccessResp(t *testing.T) {
+	r := Route{Path: "/orders", Method: method.POST, Handler: Typed(func(context.Context, struct{}) (*resp.SuccessResp, error) {
+		return &resp.SuccessResp{StatusCode: http.StatusCreated, Message: "created"}, nil
+	})}
+
+	status, body := serve(t, r, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "created", body["message"])
+}
+
+func TestTypedRoute(t *testing.T) {
+	r := TypedRoute(method.GET, "/orders/:id", func(context.Context, orderReq) (orderRes, error) {
+		return orderRes{}, nil
+	}, &Doc{Summary: "Get an order"})
+
+	require.NotNil(t, r.Doc)
+	assert.Equal(t, "Get an order", r.Doc.Summary)
+	assert.Equal(t, orderReq{}, r.Doc.Body)
+	assert.Equal(t, orderReq{}, r.Doc.Params)
+	assert.Equal(t, orderReq{}, r.Doc.Query)
+	assert.Equal(t, orderReq{}, r.Doc.Headers)
+	assert.Equal(t, orderRes{}, r.Doc.Response)
+}
@@ -0,0 +1,225 @@
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/anthanhphan/saturday/http/resp"
+	"github.com/gin-gonic/gin"
+)
+
+// regexPrefix marks an allowed origin written as a regular expression.
+const regexPrefix = "regex:"
+
+var (
+	defaultAllowMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultAllowHeaders = []string{"Origin", "Accept", "Content-Type", "Authorization", "X-Request-ID"}
+)
+
+// CORSConfig configures cross-origin resource sharing.
+//
+// Fields:
+//   - AllowOrigins: Allowed origins. "*" allows any origin, "https://*.example.com"
+//     allows subdomains, and "regex:<expression>" allows origins the whole of which
+//     matches the expression. "*" cannot be combined with AllowCredentials
+//   - AllowMethods: Methods allowed in preflight requests; defaults to GET, HEAD, POST, PUT, PATCH and DELETE
+//   - AllowHeaders: Request headers allowed in preflight requests; "*" allows any header.
+//     Defaults to Origin, Accept, Content-Type, Authorization and X-Request-ID
+//   - ExposeHeaders: Response headers readable by the browser
+//   - AllowCredentials: Whether cookies and authorization headers may be sent
+//   - MaxAge: Seconds browsers may cache a preflight response
+//   - OptionsPassthrough: Pass preflight requests on to OPTIONS routes registered with
+//     method.OPTIONS instead of answering them with 204
+//
+// Example (YAML):
+//
+//	cors:
+//	  allow_origins: ["https://app.example.com", "https://*.example.com"]
+//	  allow_credentials: true
+//	  expose_headers: ["X-Request-ID"]
+//	  max_age: 600
+type CORSConfig struct {
+	AllowOrigins       []string `yaml:"allow_origins" json:"allow_origins" validate:"required"`
+	AllowMethods       []string `yaml:"allow_methods" json:"allow_methods"`
+	AllowHeaders       []string `yaml:"allow_headers" json:"allow_headers"`
+	ExposeHeaders      []string `yaml:"expose_headers" json:"expose_headers"`
+	AllowCredentials   bool     `yaml:"allow_credentials" json:"allow_credentials"`
+	MaxAge             int      `yaml:"max_age" json:"max_age"`
+	OptionsPassthrough bool     `yaml:"options_passthrough" json:"options_passthrough"`
+}
+
+type cors struct {
+	cfg         CORSConfig
+	anyOrigin   bool
+	origins     map[string]bool
+	patterns    []*regexp.Regexp
+	methods     map[string]bool
+	anyHeader   bool
+	headers     map[string]bool
+	allowMethod string
+	allowHeader string
+	expose      string
+}
+
+// CORS creates a middleware answering preflight requests and adding the CORS
+// headers to responses for allowed origins. Preflight requests from disallowed
+// origins, or asking for disallowed methods or headers, abort with 403.
+//
+// Parameters:
+//   - cfg: The CORS configuration
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function handling CORS
+//   - error: Error if an origin pattern is invalid, or any origin is allowed with credentials
+//
+// Example:
+//
+//	handler, err := security.CORS(security.CORSConfig{AllowOrigins: []string{"https://*.example.com"}})
+func CORS(cfg CORSConfig) (gin.HandlerFunc, error) {
+	c, err := newCORS(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return c.handle, nil
+}
+
+func newCORS(cfg CORSConfig) (*cors, error) {
+	if len(cfg.AllowMethods) == 0 {
+		cfg.AllowMethods = defaultAllowMethods
+	}
+	if len(cfg.AllowHeaders) == 0 {
+		cfg.AllowHeaders = defaultAllowHeaders
+	}
+
+	c := &cors{
+		cfg:         cfg,
+		origins:     map[string]bool{},
+		methods:     map[string]bool{},
+		headers:     map[string]bool{},
+		allowMethod: strings.Join(cfg.AllowMethods, ", "),
+		allowHeader: strings.Join(cfg.AllowHeaders, ", "),
+		expose:      strings.Join(cfg.ExposeHeaders, ", "),
+	}
+
+	for _, origin := range cfg.AllowOrigins {
+		switch {
+		case origin == "*":
+			// Any site could otherwise make credentialed requests on behalf of the user
+			if cfg.AllowCredentials {
+				return nil, fmt.Errorf("allowing any origin with credentials is not permitted")
+			}
+			c.anyOrigin = true
+		case strings.HasPrefix(origin, regexPrefix):
+			// Anchored, so "https://app.example.com" does not match "https://app.example.com.evil.io"
+			pattern, err := regexp.Compile("^(?:" + strings.TrimPrefix(origin, regexPrefix) + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid origin pattern %s: %w", origin, err)
+			}
+			c.patterns = append(c.patterns, pattern)
+		case strings.Contains(origin, "*"):
+			// Wildcards match one or more host labels, never a scheme, port or path
+			parts := strings.Split(strings.ToLower(origin), "*")
+			for i := range parts {
+				parts[i] = regexp.QuoteMeta(parts[i])
+			}
+			c.patterns = append(c.patterns, regexp.MustCompile("^"+strings.Join(parts, `[a-z0-9-]+(\.[a-z0-9-]+)*`)+"$"))
+		default:
+			c.origins[strings.ToLower(origin)] = true
+		}
+	}
+	for _, m := range cfg.AllowMethods {
+		c.methods[strings.ToUpper(m)] = true
+	}
+	for _, header := range cfg.AllowHeaders {
+		if header == "*" {
+			c.anyHeader = true
+		}
+		c.headers[strings.ToLower(header)] = true
+	}
+	return c, nil
+}
+
+func (c *cors) handle(ctx *gin.Context) {
+	origin := ctx.GetHeader("Origin")
+	if origin == "" {
+		ctx.Next()
+		return
+	}
+
+	ctx.Writer.Header().Add("Vary", "Origin")
+	preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+
+	if !c.allowOrigin(origin) {
+		if preflight {
+			resp.AbortWithError(ctx, resp.ErrForbidden(fmt.Errorf("origin %s is not allowed", origin)))
+			return
+		}
+		ctx.Next()
+		return
+	}
+
+	if c.anyOrigin {
+		ctx.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		ctx.Header("Access-Control-Allow-Origin", origin)
+	}
+	if c.cfg.AllowCredentials {
+		ctx.Header("Access-Control-Allow-Credentials", "true")
+	}
+
+	if !preflight {
+		if c.expose != "" {
+			ctx.Header("Access-Control-Expose-Headers", c.expose)
+		}
+		ctx.Next()
+		return
+	}
+
+	ctx.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+	ctx.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	if requested := ctx.GetHeader("Access-Control-Request-Method"); !c.methods[strings.ToUpper(requested)] {
+		resp.AbortWithError(ctx, resp.ErrForbidden(fmt.Errorf("method %s is not allowed", requested)))
+		return
+	}
+	requestedHeaders := ctx.GetHeader("Access-Control-Request-Headers")
+	for _, header := range strings.Split(requestedHeaders, ",") {
+		if header = strings.TrimSpace(header); header != "" && !c.anyHeader && !c.headers[strings.ToLower(header)] {
+			resp.AbortWithError(ctx, resp.ErrForbidden(fmt.Errorf("header %s is not allowed", header)))
+			return
+		}
+	}
+
+	ctx.Header("Access-Control-Allow-Methods", c.allowMethod)
+	if c.anyHeader && requestedHeaders != "" {
+		ctx.Header("Access-Control-Allow-Headers", requestedHeaders)
+	} else {
+		ctx.Header("Access-Control-Allow-Headers", c.allowHeader)
+	}
+	if c.cfg.MaxAge > 0 {
+		ctx.Header("Access-Control-Max-Age", strconv.Itoa(c.cfg.MaxAge))
+	}
+
+	if c.cfg.OptionsPassthrough {
+		ctx.Next()
+		return
+	}
+	ctx.AbortWithStatus(http.StatusNoContent)
+}
+
+// allowOrigin reports whether origin matches the allowed origins.
+func (c *cors) allowOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	if c.anyOrigin || c.origins[origin] {
+		return true
+	}
+	for _, pattern := range c.patterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package security
+
+import (
+	"fmt"
+
+	"github.com/anthanhphan/saturday/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Config is the file representation of the CORS and security headers middleware.
+//
+// Fields:
+//   - CORS: CORS configuration; CORS is disabled when nil
+//   - Headers: Security headers configuration; headers are disabled when nil
+//
+// Example (YAML):
+//
+//	cors:
+//	  allow_origins: ["https://*.example.com"]
+//	  max_age: 600
+//	headers:
+//	  preset: api
+//	  skip_paths: ["/docs"]
+type Config struct {
+	CORS    *CORSConfig    `yaml:"cors" json:"cors"`
+	Headers *HeadersConfig `yaml:"headers" json:"headers"`
+}
+
+// New creates a middleware adding the configured security headers and handling
+// CORS. Register it as a global middleware, so preflight requests are answered
+// even for paths that only have non-OPTIONS routes.
+//
+// Parameters:
+//   - cfg: The configuration
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function
+//   - error: Error if the configuration is invalid
+//
+// Example:
+//
+//	handler, err := security.New(cfg.Security)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	srv := server.NewHttpServer(server.AddMiddlewares([]func(*gin.Context){handler}))
+func New(cfg Config) (gin.HandlerFunc, error) {
+	headers := func(*gin.Context) {}
+	if cfg.Headers != nil {
+		apply, err := newHeaders(*cfg.Headers)
+		if err != nil {
+			return nil, err
+		}
+		headers = apply
+	}
+	handle := func(ctx *gin.Context) { ctx.Next() }
+	if cfg.CORS != nil {
+		c, err := newCORS(*cfg.CORS)
+		if err != nil {
+			return nil, err
+		}
+		handle = c.handle
+	}
+
+	return func(ctx *gin.Context) {
+		// Headers are set first so preflight and rejected responses carry them too
+		headers(ctx)
+		handle(ctx)
+	}, nil
+}
+
+// Load reads a Config through the config package and creates its middleware.
+//
+// Parameters:
+//   - path: Path of the configuration file (YAML, JSON, TOML, ...)
+//   - opts: Options passed to config.NewConfig
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function
+//   - error: Error if the file cannot be loaded or the configuration is invalid
+//
+// Example:
+//
+//	handler, err := security.Load("./env/security.yaml")
+func Load(path string, opts ...config.Option) (gin.HandlerFunc, error) {
+	cfg, err := config.NewConfig(path, &Config{}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load security config: %w", err)
+	}
+	return New(*cfg)
+}
@@ -0,0 +1,294 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEngine(middleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware)
+	engine.GET("/items", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	engine.OPTIONS("/options", func(ctx *gin.Context) { ctx.String(http.StatusOK, "options route") })
+	engine.GET("/docs/index.html", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return engine
+}
+
+func serve(engine *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func preflight(origin, method, headers string) map[string]string {
+	return map[string]string{
+		"Origin":                         origin,
+		"Access-Control-Request-Method":  method,
+		"Access-Control-Request-Headers": headers,
+	}
+}
+
+func TestCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		allow   []string
+		origin  string
+		allowed bool
+	}{
+		{name: "exact origin", allow: []string{"https://app.example.com"}, origin: "https://app.example.com", allowed: true},
+		{name: "exact origin is case insensitive", allow: []string{"https://app.example.com"}, origin: "https://APP.example.com", allowed: true},
+		{name: "other origin", allow: []string{"https://app.example.com"}, origin: "https://evil.com", allowed: false},
+		{name: "any origin", allow: []string{"*"}, origin: "https://evil.com", allowed: true},
+		{name: "wildcard subdomain", allow: []string{"https://*.example.com"}, origin: "https://a.b.example.com", allowed: true},
+		{name: "wildcard does not match apex", allow: []string{"https://*.example.com"}, origin: "https://example.com", allowed: false},
+		{name: "wildcard does not match suffix", allow: []string{"https://*.example.com"}, origin: "https://a.example.com.evil.com", allowed: false},
+		{name: "wildcard does not match scheme", allow: []string{"https://*.example.com"}, origin: "http://a.example.com", allowed: false},
+		{name: "regex origin", allow: []string{`regex:^https://pr-\d+\.preview\.dev$`}, origin: "https://pr-42.preview.dev", allowed: true},
+		{name: "regex mismatch", allow: []string{`regex:^https://pr-\d+\.preview\.dev$`}, origin: "https://pr-x.preview.dev", allowed: false},
+		{name: "regex is anchored", allow: []string{`regex:https://pr-\d+\.preview\.dev`}, origin: "https://pr-42.preview.dev.evil.com", allowed: false},
+		{name: "regex alternatives are anchored", allow: []string{`regex:https://a\.dev|https://b\.dev`}, origin: "https://evil.com/https://b.dev", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := CORS(CORSConfig{AllowOrigins: tt.allow})
+			require.NoError(t, err)
+			engine := newTestEngine(handler)
+
+			actual := serve(engine, http.MethodGet, "/items", map[string]string{"Origin": tt.origin})
+			assert.Equal(t, http.StatusOK, actual.Code)
+			assert.Equal(t, "Origin", actual.Header().Get("Vary"))
+
+			pre := serve(engine, http.MethodOptions, "/items", preflight(tt.origin, http.MethodPost, ""))
+			if tt.allowed {
+				assert.NotEmpty(t, actual.Header().Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, http.StatusNoContent, pre.Code)
+			} else {
+				assert.Empty(t, actual.Header().Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, http.StatusForbidden, pre.Code)
+			}
+		})
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler, err := CORS(CORSConfig{
+		AllowOrigins:  []string{"https://app.example.com"},
+		AllowMethods:  []string{http.MethodGet, http.MethodPost},
+		ExposeHeaders: []string{"X-Request-ID"},
+		MaxAge:        600,
+	})
+	require.NoError(t, err)
+	engine := newTestEngine(handler)
+
+	t.Run("allowed preflight", func(t *testing.T) {
+		recorder := serve(engine, http.MethodOptions, "/items", preflight("https://app.example.com", http.MethodPost, "content-type, authorization"))
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Origin, Accept, Content-Type, Authorization, X-Request-ID", recorder.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", recorder.Header().Get("Access-Control-Max-Age"))
+		assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, recorder.Header().Values("Vary"))
+	})
+
+	t.Run("preflight of an unregistered path", func(t *testing.T) {
+		recorder := serve(engine, http.MethodOptions, "/unknown", preflight("https://app.example.com", http.MethodGet, ""))
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("disallowed method", func(t *testing.T) {
+		recorder := serve(engine, http.MethodOptions, "/items", preflight("https://app.example.com", http.MethodDelete, ""))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("disallowed header", func(t *testing.T) {
+		recorder := serve(engine, http.MethodOptions, "/items", preflight("https://app.example.com", http.MethodGet, "X-Secret"))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("actual request exposes headers", func(t *testing.T) {
+		recorder := serve(engine, http.MethodGet, "/items", map[string]string{"Origin": "https://app.example.com"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "X-Request-ID", recorder.Header().Get("Access-Control-Expose-Headers"))
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("plain OPTIONS request is not a preflight", func(t *testing.T) {
+		recorder := serve(engine, http.MethodOptions, "/options", map[string]string{"Origin": "https://app.example.com"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "options route", recorder.Body.String())
+	})
+}
+
+func TestCORSCredentialsAndPassthrough(t *testing.T) {
+	handler, err := CORS(CORSConfig{
+		AllowOrigins:       []string{"https://*.example.com"},
+		AllowHeaders:       []string{"*"},
+		AllowCredentials:   true,
+		OptionsPassthrough: true,
+	})
+	require.NoError(t, err)
+	engine := newTestEngine(handler)
+
+	recorder := serve(engine, http.MethodOptions, "/options", preflight("https://app.example.com", http.MethodGet, "X-Custom"))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "options route", recorder.Body.String())
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "X-Custom", recorder.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func TestCORSInvalidPattern(t *testing.T) {
+	_, err := CORS(CORSConfig{AllowOrigins: []string{"regex:("}})
+
+	assert.ErrorContains(t, err, "invalid origin pattern")
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	_, err := CORS(CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+
+	assert.ErrorContains(t, err, "allowing any origin with credentials is not permitted")
+}
+
+func TestHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HeadersConfig
+		path    string
+		want    map[string]string
+		missing []string
+	}{
+		{
+			name: "default api preset",
+			path: "/items",
+			want: map[string]string{
+				"X-Frame-Options":         "DENY",
+				"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
+				"Referrer-Policy":         "no-referrer",
+			},
+			missing: []string{"Permissions-Policy"},
+		},
+		{
+			name: "web preset with overrides",
+			cfg:  HeadersConfig{Preset: PresetWeb, ContentSecurityPolicy: "default-src 'self'", PermissionsPolicy: "-"},
+			path: "/items",
+			want: map[string]string{
+				"X-Frame-Options":         "SAMEORIGIN",
+				"Content-Security-Policy": "default-src 'self'",
+			},
+			missing: []string{"Permissions-Policy"},
+		},
+		{
+			name: "strict preset",
+			cfg:  HeadersConfig{Preset: PresetStrict},
+			path: "/items",
+			want: map[string]string{
+				"Strict-Transport-Security":    "max-age=63072000; includeSubDomains; preload",
+				"Cross-Origin-Resource-Policy": "same-origin",
+			},
+		},
+		{
+			name:    "none preset with one header",
+			cfg:     HeadersConfig{Preset: PresetNone, ContentTypeOptions: "nosniff"},
+			path:    "/items",
+			want:    map[string]string{"X-Content-Type-Options": "nosniff"},
+			missing: []string{"X-Frame-Options", "Strict-Transport-Security"},
+		},
+		{
+			name:    "skipped path",
+			cfg:     HeadersConfig{SkipPaths: []string{"/docs/*"}},
+			path:    "/docs/index.html",
+			missing: []string{"X-Frame-Options", "Content-Security-Policy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := Headers(tt.cfg)
+			require.NoError(t, err)
+
+			recorder := serve(newTestEngine(handler), http.MethodGet, tt.path, nil)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			for name, value := range tt.want {
+				assert.Equal(t, value, recorder.Header().Get(name), name)
+			}
+			for _, name := range tt.missing {
+				assert.Empty(t, recorder.Header().Get(name), name)
+			}
+		})
+	}
+
+	// Skipped paths are matched against the cleaned path
+	handler, err := Headers(HeadersConfig{SkipPaths: []string{"/docs/*"}})
+	require.NoError(t, err)
+	recorder := serve(newTestEngine(handler), http.MethodGet, "/docs/../items", nil)
+	assert.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+
+	_, err = Headers(HeadersConfig{Preset: "unknown"})
+	assert.ErrorContains(t, err, "unknown security headers preset")
+}
+
+func TestNew(t *testing.T) {
+	handler, err := New(Config{
+		CORS:    &CORSConfig{AllowOrigins: []string{"https://app.example.com"}},
+		Headers: &HeadersConfig{},
+	})
+	require.NoError(t, err)
+	engine := newTestEngine(handler)
+
+	recorder := serve(engine, http.MethodOptions, "/items", preflight("https://evil.com", http.MethodGet, ""))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "security.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+cors:
+  allow_origins: ["https://*.example.com"]
+  allow_credentials: true
+  max_age: 300
+headers:
+  preset: web
+  frame_options: "-"
+`), 0o600))
+
+	handler, err := Load(path)
+	require.NoError(t, err)
+
+	recorder := serve(newTestEngine(handler), http.MethodOptions, "/items", preflight("https://app.example.com", http.MethodGet, ""))
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "300", recorder.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "same-origin", recorder.Header().Get("Cross-Origin-Opener-Policy"))
+	assert.Empty(t, recorder.Header().Get("X-Frame-Options"))
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("headers:\n  preset: loose\n"), 0o600))
+
+	_, err = Load(invalid)
+	assert.ErrorContains(t, err, "failed to load security config")
+}
@@ -1,9 +1,16 @@
 package server
 
 import (
+	"net"
+	"net/http"
+	"sync"
 	"time"
 
+	"github.com/anthanhphan/saturday/http/authz"
+	"github.com/anthanhphan/saturday/http/middlewares"
+	"github.com/anthanhphan/saturday/http/openapi"
 	"github.com/anthanhphan/saturday/http/route"
+	"github.com/anthanhphan/saturday/metrics"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,6 +29,26 @@ type Option func(*HttpServer)
 //   - GinOptions: Gin-specific configuration options
 //   - OnCloseFunc: Function to execute on server shutdown
 //   - GracefulShutdownTimeout: Maximum time to wait for graceful shutdown
+//   - TLS: TLS configuration; nil serves plain HTTP
+//   - ReadTimeout: Maximum duration for reading the entire request, including the body
+//   - ReadHeaderTimeout: Maximum duration for reading the request headers
+//   - WriteTimeout: Maximum duration before timing out writes of the response
+//   - IdleTimeout: Maximum time to wait for the next request on keep-alive connections
+//   - UnixSocket: Path of a Unix socket to listen on instead of Port
+//   - Listener: Already-open listener to serve on instead of Port or UnixSocket
+//   - OpenAPI: OpenAPI document settings; nil disables the document and docs UI
+//   - Policy: Authorization policy enforcing the Permissions of routes and groups
+//   - RequestIdOptions: Options of the request ID middleware
+//   - Metrics: Metrics recording the requests and served on MetricsPath
+//   - MetricsPath: Path of the metrics route, /metrics by default
+//   - Tracing: Whether to run each request in a tracing span
+//   - TracingOptions: Options of the tracing middleware
+//   - AccessLog: Whether to write an access log line per request
+//   - AccessLogOptions: Options of the access log middleware
+//   - Compression: Whether to compress responses
+//   - CompressOptions: Options of the compression middleware
+//   - ETag: Whether to add ETags and answer conditional requests
+//   - ETagOptions: Options of the ETag middleware
 type HttpServer struct {
 	Name                    string
 	Port                    int64
@@ -32,6 +59,30 @@ type HttpServer struct {
 	GinOptions              []route.GinOption
 	OnCloseFunc             func()
 	GracefulShutdownTimeout time.Duration
+	TLS                     *TLSConfig
+	ReadTimeout             time.Duration
+	ReadHeaderTimeout       time.Duration
+	WriteTimeout            time.Duration
+	IdleTimeout             time.Duration
+	UnixSocket              string
+	Listener                net.Listener
+	OpenAPI                 *openapi.Config
+	Policy                  *authz.Policy
+	RequestIdOptions        []middlewares.RequestIdOption
+	Metrics                 *metrics.Metrics
+	MetricsPath             string
+	Tracing                 bool
+	TracingOptions          []middlewares.TracingOption
+	AccessLog               bool
+	AccessLogOptions        []middlewares.AccessLogOption
+	Compression             bool
+	CompressOptions         []middlewares.CompressOption
+	ETag                    bool
+	ETagOptions             []middlewares.ETagOption
+
+	mu         sync.Mutex
+	httpServer *http.Server
+	closeOnce  *sync.Once
 }
 
 // NewHttpServer creates a new HTTP server instance with the provided options.
@@ -111,6 +162,23 @@ func AddMiddlewares(middleware []func(c *gin.Context)) Option {
 	}
 }
 
+// AddGinOptions returns an Option to append Gin engine options.
+//
+// Parameters:
+//   - options: Variable number of GinOption functions to apply to the engine
+//
+// Returns:
+//   - Option: Function that adds the Gin options to the HttpServer
+//
+// Example:
+//
+//	server := NewHttpServer(AddGinOptions(route.SetMaximumMultipartSize(8 << 20)))
+func AddGinOptions(options ...route.GinOption) Option {
+	return func(server *HttpServer) {
+		server.GinOptions = append(server.GinOptions, options...)
+	}
+}
+
 // AddRoutes adds individual routes to the server configuration.
 //
 // Parameters:
@@ -174,3 +242,283 @@ func SetGracefulShutdownTimeout(t time.Duration) Option {
 		server.GracefulShutdownTimeout = t
 	}
 }
+
+// SetTLS returns an Option to serve HTTPS. Setting ClientCAFile enables mutual TLS.
+//
+// Parameters:
+//   - tlsConfig: TLS configuration with certificate and key files
+//
+// Returns:
+//   - Option: Function that sets the TLS configuration
+//
+// Example:
+//
+//	server := NewHttpServer(SetTLS(&TLSConfig{
+//	    CertFile:     "./certs/server.crt",
+//	    KeyFile:      "./certs/server.key",
+//	    ClientCAFile: "./certs/ca.crt",
+//	}))
+func SetTLS(tlsConfig *TLSConfig) Option {
+	return func(server *HttpServer) {
+		server.TLS = tlsConfig
+	}
+}
+
+// SetReadTimeout returns an Option to set the maximum duration for reading an entire request.
+//
+// Parameters:
+//   - t: Read timeout duration
+//
+// Returns:
+//   - Option: Function that sets the read timeout
+//
+// Example:
+//
+//	server := NewHttpServer(SetReadTimeout(15 * time.Second))
+func SetReadTimeout(t time.Duration) Option {
+	return func(server *HttpServer) {
+		server.ReadTimeout = t
+	}
+}
+
+// SetReadHeaderTimeout returns an Option to set the maximum duration for reading request headers.
+//
+// Parameters:
+//   - t: Read header timeout duration
+//
+// Returns:
+//   - Option: Function that sets the read header timeout
+//
+// Example:
+//
+//	server := NewHttpServer(SetReadHeaderTimeout(5 * time.Second))
+func SetReadHeaderTimeout(t time.Duration) Option {
+	return func(server *HttpServer) {
+		server.ReadHeaderTimeout = t
+	}
+}
+
+// SetWriteTimeout returns an Option to set the maximum duration before timing out response writes.
+//
+// Parameters:
+//   - t: Write timeout duration
+//
+// Returns:
+//   - Option: Function that sets the write timeout
+//
+// Example:
+//
+//	server := NewHttpServer(SetWriteTimeout(30 * time.Second))
+func SetWriteTimeout(t time.Duration) Option {
+	return func(server *HttpServer) {
+		server.WriteTimeout = t
+	}
+}
+
+// SetIdleTimeout returns an Option to set the keep-alive idle timeout.
+//
+// Parameters:
+//   - t: Idle timeout duration
+//
+// Returns:
+//   - Option: Function that sets the idle timeout
+//
+// Example:
+//
+//	server := NewHttpServer(SetIdleTimeout(2 * time.Minute))
+func SetIdleTimeout(t time.Duration) Option {
+	return func(server *HttpServer) {
+		server.IdleTimeout = t
+	}
+}
+
+// SetUnixSocket returns an Option to listen on a Unix socket instead of a TCP port.
+//
+// Parameters:
+//   - path: Path of the socket file; a stale socket at this path is removed on start
+//
+// Returns:
+//   - Option: Function that sets the socket path
+//
+// Example:
+//
+//	server := NewHttpServer(SetUnixSocket("/run/api.sock"))
+func SetUnixSocket(path string) Option {
+	return func(server *HttpServer) {
+		server.UnixSocket = path
+	}
+}
+
+// SetListener returns an Option to serve on an already-open listener, e.g. in tests.
+//
+// Parameters:
+//   - listener: The listener to serve on; it is closed on shutdown
+//
+// Returns:
+//   - Option: Function that sets the listener
+//
+// Example:
+//
+//	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+//	server := NewHttpServer(SetListener(listener))
+func SetListener(listener net.Listener) Option {
+	return func(server *HttpServer) {
+		server.Listener = listener
+	}
+}
+
+// SetOpenAPI returns an Option to serve an OpenAPI 3 document generated from the
+// server's routes, together with a docs UI.
+//
+// Parameters:
+//   - cfg: Document metadata and serving paths
+//
+// Returns:
+//   - Option: Function that sets the OpenAPI configuration
+//
+// Example:
+//
+//	server := NewHttpServer(SetOpenAPI(&openapi.Config{Title: "Order API", Version: "1.2.0"}))
+//	// GET /openapi.json and GET /docs
+func SetOpenAPI(cfg *openapi.Config) Option {
+	return func(server *HttpServer) {
+		server.OpenAPI = cfg
+	}
+}
+
+// SetPolicy returns an Option to set the authorization policy. Routes and groups
+// declaring Permissions are denied with 403 unless the requester holds them; without
+// a policy they are always denied.
+//
+// Parameters:
+//   - policy: The authorization policy
+//
+// Returns:
+//   - Option: Function that sets the policy
+//
+// Example:
+//
+//	policy, err := authz.LoadPolicy("./env/policy.yaml")
+//	server := NewHttpServer(
+//	    AddMiddlewares([]func(*gin.Context){middlewares.Auth(jwtService)}),
+//	    SetPolicy(policy),
+//	)
+func SetPolicy(policy *authz.Policy) Option {
+	return func(server *HttpServer) {
+		server.Policy = policy
+	}
+}
+
+// SetRequestId returns an Option to configure the request ID middleware,
+// e.g. the inbound header names or the ID generator.
+//
+// Parameters:
+//   - opts: Variable number of RequestIdOption functions
+//
+// Returns:
+//   - Option: Function that sets the request ID options
+//
+// Example:
+//
+//	server := NewHttpServer(SetRequestId(middlewares.RequestIdGenerator(middlewares.NewUUIDv7)))
+func SetRequestId(opts ...middlewares.RequestIdOption) Option {
+	return func(server *HttpServer) {
+		server.RequestIdOptions = opts
+	}
+}
+
+// SetMetrics returns an Option to record request metrics and serve them.
+//
+// Parameters:
+//   - m: The metrics, usually created by metrics.InitMetrics
+//   - path: Optional path of the metrics route; defaults to /metrics
+//
+// Returns:
+//   - Option: Function that sets the metrics
+//
+// Example:
+//
+//	m, undo := metrics.InitMetrics(&metrics.Config{Namespace: "orders"})
+//	defer undo()
+//	server := NewHttpServer(SetMetrics(m))
+func SetMetrics(m *metrics.Metrics, path ...string) Option {
+	return func(server *HttpServer) {
+		server.Metrics = m
+		if len(path) > 0 {
+			server.MetricsPath = path[0]
+		}
+	}
+}
+
+// SetTracing returns an Option to enable the tracing middleware. Spans are
+// exported by the provider set up with tracing.InitTracer.
+//
+// Parameters:
+//   - opts: Variable number of TracingOption functions
+//
+// Returns:
+//   - Option: Function that enables tracing
+//
+// Example:
+//
+//	server := NewHttpServer(SetTracing(middlewares.TracingSkipPaths("/health-check")))
+func SetTracing(opts ...middlewares.TracingOption) Option {
+	return func(server *HttpServer) {
+		server.Tracing = true
+		server.TracingOptions = opts
+	}
+}
+
+// SetAccessLog returns an Option to enable the access log middleware.
+//
+// Parameters:
+//   - opts: Variable number of AccessLogOption functions
+//
+// Returns:
+//   - Option: Function that enables the access log
+//
+// Example:
+//
+//	server := NewHttpServer(SetAccessLog(middlewares.AccessLogSkipPaths("/health-check")))
+func SetAccessLog(opts ...middlewares.AccessLogOption) Option {
+	return func(server *HttpServer) {
+		server.AccessLog = true
+		server.AccessLogOptions = opts
+	}
+}
+
+// SetCompression returns an Option to enable the compression middleware.
+//
+// Parameters:
+//   - opts: Variable number of CompressOption functions
+//
+// Returns:
+//   - Option: Function that enables compression
+//
+// Example:
+//
+//	server := NewHttpServer(SetCompression(middlewares.CompressMinSize(512)))
+func SetCompression(opts ...middlewares.CompressOption) Option {
+	return func(server *HttpServer) {
+		server.Compression = true
+		server.CompressOptions = opts
+	}
+}
+
+// SetETag returns an Option to enable the ETag middleware.
+//
+// Parameters:
+//   - opts: Variable number of ETagOption functions
+//
+// Returns:
+//   - Option: Function that enables ETags
+//
+// Example:
+//
+//	server := NewHttpServer(SetETag(middlewares.ETagSkipPaths("/events")))
+func SetETag(opts ...middlewares.ETagOption) Option {
+	return func(server *HttpServer) {
+		server.ETag = true
+		server.ETagOptions = opts
+	}
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/authz"
+	"github.com/anthanhphan/saturday/http/middlewares"
+	"github.com/anthanhphan/saturday/http/openapi"
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const defaultGracefulShutdownTimeout = 5 * time.Second
+
+// Engine builds the Gin engine serving the configured routes and, when OpenAPI
+// is set, the generated document, and when Metrics is set, the metrics route.
+// Request ID, metrics, tracing, compression, access logging, ETags, panic
+// recovery and error handling run before the configured middlewares, and route
+// Permissions are enforced by Policy.
+//
+// Returns:
+//   - *gin.Engine: The configured engine
+//
+// Example:
+//
+//	recorder := httptest.NewRecorder()
+//	server.Engine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health-check", nil))
+func (server *HttpServer) Engine() *gin.Engine {
+	handlers := []func(*gin.Context){middlewares.RequestId(server.RequestIdOptions...)}
+	if server.Metrics != nil {
+		handlers = append(handlers, server.Metrics.Middleware())
+	}
+	if server.Tracing {
+		handlers = append(handlers, middlewares.Tracing(server.TracingOptions...))
+	}
+	if server.Compression {
+		handlers = append(handlers, middlewares.Compress(server.CompressOptions...))
+	}
+	// Inside Compress, so sizes and bodies are logged uncompressed
+	if server.AccessLog {
+		handlers = append(handlers, middlewares.AccessLog(server.AccessLogOptions...))
+	}
+	if server.ETag {
+		handlers = append(handlers, middlewares.ETag(server.ETagOptions...))
+	}
+	handlers = append(append(handlers, middlewares.Recover(), middlewares.ErrorHandler()), server.Middlewares...)
+
+	options := []route.GinOption{
+		route.AddMiddlewares(handlers...),
+		route.AddHealthCheckRoute(),
+		route.AddRouteNotFoundHandler(),
+		route.SetStrictSlash(server.StrictSlash),
+		route.AddGroupRoutes(authz.ProtectGroups(server.Policy, server.GroupRoutes)),
+		route.AddRoutes(authz.Protect(server.Policy, server.Routes)),
+	}
+	if server.OpenAPI != nil {
+		options = append(options, openapi.AddDocsRoutes(*server.OpenAPI, server.Routes, server.GroupRoutes))
+	}
+	if server.Metrics != nil {
+		options = append(options, server.Metrics.AddMetricsRoute(server.MetricsPath))
+	}
+
+	return route.NewGinEngine(append(options, server.GinOptions...)...)
+}
+
+// Start serves HTTP(S) and blocks until ctx is cancelled or the server fails.
+// On cancellation the server is shut down gracefully within GracefulShutdownTimeout
+// and OnCloseFunc is called.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the server
+//
+// Returns:
+//   - error: Error if the server cannot listen or stops unexpectedly, or if the graceful shutdown fails
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+//	defer stop()
+//	if err := server.Start(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+func (server *HttpServer) Start(ctx context.Context) error {
+	log := zap.L().With(zap.String("prefix", "Start")).Sugar()
+
+	httpServer := &http.Server{
+		Handler:           server.Engine(),
+		ReadTimeout:       server.ReadTimeout,
+		ReadHeaderTimeout: server.ReadHeaderTimeout,
+		WriteTimeout:      server.WriteTimeout,
+		IdleTimeout:       server.IdleTimeout,
+	}
+
+	if server.TLS != nil {
+		tlsConfig, err := server.TLS.Build()
+		if err != nil {
+			return err
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
+
+	server.mu.Lock()
+	if server.httpServer != nil {
+		server.mu.Unlock()
+		return errors.New("http server is already started")
+	}
+	listener, err := server.listen()
+	if err != nil {
+		server.mu.Unlock()
+		return err
+	}
+	server.httpServer = httpServer
+	server.closeOnce = &sync.Once{}
+	server.mu.Unlock()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if httpServer.TLSConfig != nil {
+			serveErr <- httpServer.ServeTLS(listener, "", "")
+			return
+		}
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	log.Infof("http server %s started on %s", server.Name, listener.Addr())
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		server.reset(httpServer)
+		return fmt.Errorf("http server stopped: %w", err)
+	case <-ctx.Done():
+		log.Infof("shutting down http server %s...", server.Name)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), server.gracefulShutdownTimeout())
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+
+		log.Infof("http server %s exited gracefully", server.Name)
+		return nil
+	}
+}
+
+// Shutdown gracefully stops a started server: it stops accepting connections,
+// waits for in-flight requests until ctx expires and then calls OnCloseFunc.
+// It does nothing if the server is not running.
+//
+// Parameters:
+//   - ctx: Context bounding the wait for in-flight requests
+//
+// Returns:
+//   - error: Error if in-flight requests did not finish in time
+func (server *HttpServer) Shutdown(ctx context.Context) error {
+	server.mu.Lock()
+	httpServer, closeOnce := server.httpServer, server.closeOnce
+	server.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	err := httpServer.Shutdown(ctx)
+	server.reset(httpServer)
+
+	closeOnce.Do(func() {
+		if server.OnCloseFunc != nil {
+			server.OnCloseFunc()
+		}
+	})
+
+	if err != nil {
+		return fmt.Errorf("server forced to shutdown: %w", err)
+	}
+	return nil
+}
+
+// listen opens the configured listener, Unix socket or TCP port.
+func (server *HttpServer) listen() (net.Listener, error) {
+	if server.Listener != nil {
+		return server.Listener, nil
+	}
+
+	if server.UnixSocket != "" {
+		if info, err := os.Stat(server.UnixSocket); err == nil && info.Mode().Type() == fs.ModeSocket {
+			if err := os.Remove(server.UnixSocket); err != nil {
+				return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+			}
+		}
+
+		listener, err := net.Listen("unix", server.UnixSocket)
+		if err != nil {
+			return nil, fmt.Errorf("could not listen on %s: %w", server.UnixSocket, err)
+		}
+		return listener, nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", server.Port))
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on port %d: %w", server.Port, err)
+	}
+	return listener, nil
+}
+
+// reset forgets httpServer so the server can be started again.
+func (server *HttpServer) reset(httpServer *http.Server) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.httpServer == httpServer {
+		server.httpServer = nil
+	}
+}
+
+func (server *HttpServer) gracefulShutdownTimeout() time.Duration {
+	if server.GracefulShutdownTimeout > 0 {
+		return server.GracefulShutdownTimeout
+	}
+	return defaultGracefulShutdownTimeout
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// startServer runs server.Start in the background and returns a function that
+// cancels it and returns Start's error.
+func startServer(t *testing.T, server *HttpServer) func() error {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.httpServer != nil
+	}, time.Second, 5*time.Millisecond)
+
+	return func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not stop")
+			return nil
+		}
+	}
+}
+
+func TestStartListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	closed := false
+	server := NewHttpServer(
+		SetListener(listener),
+		SetReadHeaderTimeout(time.Second),
+		SetGracefulShutdownTimeout(time.Second),
+	)
+	server.OnCloseFunc = func() { closed = true }
+	stop := startServer(t, server)
+
+	res, err := http.Get("http://" + listener.Addr().String() + "/health-check")
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	require.NoError(t, stop())
+	assert.True(t, closed)
+	assert.NoError(t, server.Shutdown(context.Background()))
+}
+
+func TestStartUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	server := NewHttpServer(SetUnixSocket(socket))
+	stop := startServer(t, server)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	res, err := client.Get("http://unix/health-check")
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	require.NoError(t, stop())
+}
+
+func TestStartMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := writeCert(t, dir, "ca", nil, nil)
+	writeCert(t, dir, "server", caCert, caKey)
+	writeCert(t, dir, "client", caCert, caKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := NewHttpServer(
+		SetListener(listener),
+		SetTLS(&TLSConfig{
+			CertFile:     filepath.Join(dir, "server.crt"),
+			KeyFile:      filepath.Join(dir, "server.key"),
+			ClientCAFile: filepath.Join(dir, "ca.crt"),
+		}),
+	)
+	stop := startServer(t, server)
+	defer func() { require.NoError(t, stop()) }()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	url := "https://" + listener.Addr().String() + "/health-check"
+
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	_, err = anonymous.Get(url)
+	assert.Error(t, err)
+
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"))
+	require.NoError(t, err)
+	authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{keyPair},
+	}}}
+	res, err := authenticated.Get(url)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestTLSConfigBuildErrors(t *testing.T) {
+	_, err := (&TLSConfig{}).Build()
+	assert.Error(t, err)
+
+	_, err = (&TLSConfig{CertFile: "missing.crt", KeyFile: "missing.key"}).Build()
+	assert.ErrorContains(t, err, "failed to load tls key pair")
+}
+
+// writeCert writes <name>.crt and <name>.key into dir. The certificate is a CA
+// when parent is nil and is signed by parent otherwise.
+func writeCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return cert, key
+}
+
+func TestEngineAccessLogWithCompression(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+
+	body := strings.Repeat("a", 4096)
+	server := NewHttpServer(
+		SetAccessLog(middlewares.AccessLogBody(8192)),
+		SetCompression(),
+		AddGinOptions(func(g *gin.Engine) {
+			g.GET("/items", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"body": body}) })
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	server.Engine().ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Less(t, recorder.Body.Len(), 4096)
+
+	access := logs.FilterMessage("access log").All()
+	require.Len(t, access, 1)
+	fields := access[0].ContextMap()
+	assert.EqualValues(t, len(`{"body":""}`)+len(body), fields["bytes_out"])
+	assert.JSONEq(t, `{"body":"`+body+`"}`, fields["response_body"].(string))
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig describes the certificates used to serve HTTPS.
+//
+// Fields:
+//   - CertFile: Path of the PEM-encoded server certificate (chain)
+//   - KeyFile: Path of the PEM-encoded private key
+//   - ClientCAFile: Path of the PEM-encoded CA bundle used to verify client certificates; enables mutual TLS
+//   - ClientAuth: Client certificate policy (defaults to tls.RequireAndVerifyClientCert when ClientCAFile is set)
+//   - MinVersion: Minimum TLS version (defaults to tls.VersionTLS12)
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   tls.ClientAuthType
+	MinVersion   uint16
+}
+
+// Build loads the certificates and returns the corresponding *tls.Config.
+//
+// Returns:
+//   - *tls.Config: The TLS configuration for http.Server
+//   - error: Error if a file cannot be read or parsed
+func (c *TLSConfig) Build() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("tls cert file and key file are required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tls key pair: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   c.MinVersion,
+		ClientAuth:   c.ClientAuth,
+	}
+	if tlsConfig.MinVersion == 0 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", c.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		if tlsConfig.ClientAuth == tls.NoClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return tlsConfig, nil
+}
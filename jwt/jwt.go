@@ -39,12 +39,15 @@ func (j *jwt) Generate(payload *Payload, expiry int64) (*string, error) {
 		return nil, fmt.Errorf("invalid payload: %v", err)
 	}
 
-	// Create a new JWT token with the specified claims
-	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, gojwt.MapClaims{
-		"user_id": payload.UserId,
-		"exp":     time.Now().Add(time.Second * time.Duration(expiry)).Unix(),
-		"iat":     time.Now().Unix(),
-	})
+	// Create a new JWT token with the additional and the registered claims
+	claims := gojwt.MapClaims{}
+	for key, value := range payload.Claims {
+		claims[key] = value
+	}
+	claims["user_id"] = payload.UserId
+	claims["exp"] = time.Now().Add(time.Second * time.Duration(expiry)).Unix()
+	claims["iat"] = time.Now().Unix()
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
 
 	// Parse the RSA private key for signing the token
 	key, err := gojwt.ParseRSAPrivateKeyFromPEM(j.privateKey)
@@ -97,7 +100,13 @@ func (j *jwt) Validate(tokenString string) (*Payload, error) {
 		return nil, fmt.Errorf("missing user_id in token claims")
 	}
 	// Return the payload extracted from the claims
+	userId, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid user_id in token claims")
+	}
+
 	return &Payload{
-		UserId: int64(claims["user_id"].(float64)),
+		UserId: int64(userId),
+		Claims: claims,
 	}, nil
 }
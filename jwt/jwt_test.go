@@ -197,3 +197,22 @@ func TestGenerateWithEmptyClaims(t *testing.T) {
 	_, err = jwtService.Generate(payload, 3600)
 	assert.Error(t, err)
 }
+
+func TestGenerateAndValidateWithClaims(t *testing.T) {
+	rsaKeys, err := utils.GenerateRsaKeyPair()
+	assert.NoError(t, err)
+
+	jwtService := NewJwt(rsaKeys.PrivateKey, rsaKeys.PublicKey)
+
+	tokenString, err := jwtService.Generate(&Payload{
+		UserId: 123,
+		Claims: map[string]any{"roles": []string{"admin"}, "user_id": 999},
+	}, 3600)
+	assert.NoError(t, err)
+
+	payload, err := jwtService.Validate(*tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), payload.UserId)
+	assert.Equal(t, []any{"admin"}, payload.Claims["roles"])
+	assert.Equal(t, float64(123), payload.Claims["user_id"])
+}
@@ -1,5 +1,9 @@
 package jwt
 
+// Payload holds the claims of a token. Claims carries additional claims such as
+// roles; Generate adds them to the token and Validate returns every claim of the
+// token in it, including user_id, exp and iat.
 type Payload struct {
-	UserId int64 `json:"user_id" validate:"required"`
+	UserId int64          `json:"user_id" validate:"required"`
+	Claims map[string]any `json:"claims,omitempty"`
 }
@@ -1,15 +1,18 @@
 package kafka
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/anthanhphan/saturday/metrics"
 	"go.uber.org/zap"
 )
 
 
-var _ IPublisher = (*asyncPublisher)(nil)
+var _ ContextPublisher = (*asyncPublisher)(nil)
 
 type asyncPublisher struct {
 	producer        sarama.AsyncProducer
@@ -22,6 +25,7 @@ func NewAsyncPublisher(cfg Config) IPublisher {
 	config := sarama.NewConfig()
 	config.Producer.RequiredAcks = sarama.WaitForLocal
 	config.Producer.Flush.Frequency = 50 * time.Millisecond
+	config.Producer.Return.Successes = true
 	if cfg.MaxMessageBytes > 0 {
 		config.Producer.MaxMessageBytes = int(cfg.MaxMessageBytes * MB)
 	}
@@ -32,7 +36,7 @@ func NewAsyncPublisher(cfg Config) IPublisher {
 		config.Net.SASL.Enable = true
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
 		config.Net.SASL.User = cfg.Acl.User
-		config.Net.SASL.Password = cfg.Acl.Password
+		config.Net.SASL.Password = cfg.Acl.Password.Value()
 		config.Net.SASL.Handshake = true
 		config.Net.TLS.Enable = false
 		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
@@ -53,6 +57,12 @@ func NewAsyncPublisher(cfg Config) IPublisher {
 	go func() {
 		for err := range producer.Errors() {
 			log.Errorf("Failed to write entry err: %v", err)
+			metrics.Global().ObservePublish(err.Msg.Topic, err.Err)
+		}
+	}()
+	go func() {
+		for msg := range producer.Successes() {
+			metrics.Global().ObservePublish(msg.Topic, nil)
 		}
 	}()
 
@@ -73,9 +83,7 @@ func (p *asyncPublisher) Write(v interface{}, topic string) error {
 		Topic: topic,
 		Value: sarama.ByteEncoder(data),
 	}
-	if int64(len(data)) > warningMessageSize || int64(len(data)) >= p.maxMessageBytes {
-		log.Warnf("topic: %v, messageLen=%v, maxMessageByte=%v", topic, len(data), p.maxMessageBytes)
-	}
+	observeMessageSize(log, topic, len(data), p.maxMessageBytes)
 	return nil
 }
 
@@ -91,9 +99,7 @@ func (p *asyncPublisher) WriteWithKey(v interface{}, key, topic string) error {
 		Topic: topic,
 		Value: sarama.ByteEncoder(data),
 	}
-	if int64(len(data)) > warningMessageSize || int64(len(data)) >= p.maxMessageBytes {
-		log.Warnf("topic: %v, messageLen=%v, maxMessageByte=%v", topic, len(data), p.maxMessageBytes)
-	}
+	observeMessageSize(log, topic, len(data), p.maxMessageBytes)
 	return nil
 }
 
@@ -105,10 +111,38 @@ func (p *asyncPublisher) WriteStringWithKey(v, key, topic string) error {
 		Topic: topic,
 		Value: sarama.StringEncoder(v),
 	}
-	if int64(len(v)) > warningMessageSize || int64(len(v)) >= p.maxMessageBytes {
-		log.Warnf("topic: %v, messageLen=%v, maxMessageByte=%v", topic, len(v), p.maxMessageBytes)
-	}
+	observeMessageSize(log, topic, len(v), p.maxMessageBytes)
+
+	return nil
+}
 
+// WriteContext publishes value as JSON in a producer span, with the request ID
+// and W3C traceparent of ctx as record headers, so consumers continue the same
+// request and trace. An empty key publishes without a key.
+//
+// Parameters:
+//   - ctx: Request context, e.g. ctx.Request.Context() in a handler
+//   - v: Value to marshal
+//   - key: Message key
+//   - topic: Destination topic
+//
+// Returns:
+//   - error: Error if the value cannot be marshalled or sent
+//
+// Example:
+//
+//	err := publisher.WriteContext(ctx, order, strconv.FormatInt(order.ID, 10), "orders")
+func (p *asyncPublisher) WriteContext(ctx context.Context, v interface{}, key, topic string) error {
+	log := logger.FromContext(ctx).With(zap.String("prefix", "WriteContext")).Sugar()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	ctx, span := startPublishSpan(ctx, topic, len(data))
+	p.producer.Input() <- newMessage(ctx, data, key, topic)
+	span.End()
+	observeMessageSize(log, topic, len(data), p.maxMessageBytes)
 	return nil
 }
 
@@ -1,7 +1,9 @@
 package kafka
 
+import "github.com/anthanhphan/saturday/secret"
+
 type Config struct {
-	Addrs           []string `json:"addrs" yaml:"addrs"`
+	Addrs           []string `json:"addrs" yaml:"addrs" validate:"min=1"`
 	Topics          []string `json:"topics" yaml:"topics"`
 	Group           string   `json:"group" yaml:"group"`
 	GroupId         string   `json:"group_id" yaml:"group_id"`
@@ -16,8 +18,8 @@ type Config struct {
 		ReturnErrors           *bool `json:"return_errors" yaml:"return_errors"`
 	}
 	Acl struct {
-		Enable   bool   `json:"enable" yaml:"enable"`
-		User     string `json:"user" yaml:"user"`
-		Password string `json:"password" yaml:"password"`
+		Enable   bool          `json:"enable" yaml:"enable"`
+		User     string        `json:"user" yaml:"user"`
+		Password secret.Secret `json:"password" yaml:"password"`
 	}
 }
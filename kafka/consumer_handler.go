@@ -2,9 +2,10 @@ package kafka
 
 import (
 	"context"
+	"time"
 
 	"github.com/IBM/sarama"
-	"github.com/google/uuid"
+	"github.com/anthanhphan/saturday/metrics"
 	"go.uber.org/zap"
 )
 
@@ -29,10 +30,11 @@ func (c *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, clai
 		select {
 		case msg, ok := <-claim.Messages():
 			if ok {
-				requestID := uuid.New().String()
-				ctx := context.Background()
-				ctx = context.WithValue(ctx, "request_id", requestID)
+				start := time.Now()
+				ctx, span := startProcessSpan(messageContext(context.Background(), msg.Headers), c.group, msg)
 				err := c.fc(ctx, msg.Topic, msg.Value)
+				endSpan(span, err)
+				metrics.Global().ObserveProcess(msg.Topic, c.group, time.Since(start), err)
 				if err != nil {
 					continue
 				}
@@ -0,0 +1,155 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/anthanhphan/saturday/reqctx"
+	"github.com/anthanhphan/saturday/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Record headers carrying the request correlation of a message
+const (
+	HeaderRequestId   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+)
+
+// producerHeaders adapts the headers of a producer message to a propagation.TextMapCarrier.
+type producerHeaders struct {
+	msg *sarama.ProducerMessage
+}
+
+func (h producerHeaders) Get(key string) string {
+	for _, header := range h.msg.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (h producerHeaders) Set(key, value string) {
+	for i, header := range h.msg.Headers {
+		if string(header.Key) == key {
+			h.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	h.msg.Headers = append(h.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (h producerHeaders) Keys() []string {
+	keys := make([]string, 0, len(h.msg.Headers))
+	for _, header := range h.msg.Headers {
+		keys = append(keys, string(header.Key))
+	}
+	return keys
+}
+
+// consumerHeaders adapts the headers of a consumed message to a propagation.TextMapCarrier.
+type consumerHeaders []*sarama.RecordHeader
+
+func (h consumerHeaders) Get(key string) string {
+	for _, header := range h {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (h consumerHeaders) Set(string, string) {}
+
+func (h consumerHeaders) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for _, header := range h {
+		if header != nil {
+			keys = append(keys, string(header.Key))
+		}
+	}
+	return keys
+}
+
+// newMessage builds a producer message carrying the request ID and trace
+// context of ctx as record headers.
+func newMessage(ctx context.Context, data []byte, key, topic string) *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(data),
+	}
+	if key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+
+	headers := producerHeaders{msg: msg}
+	if requestId := reqctx.GetRequestId(ctx); requestId != "" {
+		headers.Set(HeaderRequestId, requestId)
+	}
+	tracing.Inject(ctx, headers)
+	return msg
+}
+
+// startPublishSpan starts the producer span of a message published to topic.
+func startPublishSpan(ctx context.Context, topic string, size int) (context.Context, trace.Span) {
+	return tracing.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "publish"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.Int("messaging.message.body.size", size),
+		),
+	)
+}
+
+// endSpan records err on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// messageContext returns a context carrying the request ID and trace parent of
+// the record headers. Messages without a request ID get a new one.
+func messageContext(ctx context.Context, headers []*sarama.RecordHeader) context.Context {
+	carrier := consumerHeaders(headers)
+
+	requestId := carrier.Get(HeaderRequestId)
+	if requestId == "" {
+		requestId = uuid.New().String()
+	}
+	ctx = reqctx.SetRequestId(ctx, requestId)
+	if tp, err := reqctx.ParseTraceParent(carrier.Get(HeaderTraceParent)); err == nil {
+		ctx = reqctx.SetTraceParent(ctx, tp)
+	}
+
+	// Also kept under the historical "request_id" key read by existing callbacks
+	return context.WithValue(tracing.Extract(ctx, carrier), "request_id", requestId)
+}
+
+// startProcessSpan starts the consumer span of msg. The span continues the
+// producer's trace and links to the producer's span.
+func startProcessSpan(ctx context.Context, group string, msg *sarama.ConsumerMessage) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "process"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.String("messaging.consumer.group.name", group),
+			attribute.Int("messaging.destination.partition.id", int(msg.Partition)),
+			attribute.Int64("messaging.kafka.offset", msg.Offset),
+			attribute.Int("messaging.message.body.size", len(msg.Value)),
+		),
+	}
+	if producer := trace.SpanContextFromContext(ctx); producer.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: producer}))
+	}
+	return tracing.Start(ctx, msg.Topic+" process", opts...)
+}
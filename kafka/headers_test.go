@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/anthanhphan/saturday/reqctx"
+	"github.com/anthanhphan/saturday/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMessageHeaders(t *testing.T) {
+	tp, err := reqctx.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+
+	ctx := reqctx.SetTraceParent(reqctx.SetRequestId(context.Background(), "req-1"), tp)
+	msg := newMessage(ctx, []byte("{}"), "", "orders")
+	assert.Nil(t, msg.Key)
+	require.Len(t, msg.Headers, 2)
+
+	headers := make([]*sarama.RecordHeader, len(msg.Headers))
+	for i := range msg.Headers {
+		headers[i] = &msg.Headers[i]
+	}
+
+	consumed := messageContext(context.Background(), headers)
+	assert.Equal(t, "req-1", reqctx.GetRequestId(consumed))
+	got, ok := reqctx.GetTraceParent(consumed)
+	assert.True(t, ok)
+	assert.Equal(t, tp, got)
+
+	// Messages published without headers get a fresh request ID
+	assert.NotEmpty(t, reqctx.GetRequestId(messageContext(context.Background(), nil)))
+}
+
+func TestMessageSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, undo := tracing.InitTracer(&tracing.Config{ServiceName: "test"}, exporter)
+	defer undo()
+
+	ctx, publish := startPublishSpan(context.Background(), "orders", 2)
+	msg := newMessage(ctx, []byte("{}"), "key", "orders")
+	endSpan(publish, nil)
+
+	consumed := &sarama.ConsumerMessage{Topic: "orders", Value: []byte("{}")}
+	for i := range msg.Headers {
+		consumed.Headers = append(consumed.Headers, &msg.Headers[i])
+	}
+	_, process := startProcessSpan(messageContext(context.Background(), consumed.Headers), "group", consumed)
+	endSpan(process, errors.New("failed"))
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	producer, consumer := spans[0], spans[1]
+	assert.Equal(t, "orders publish", producer.Name)
+	assert.Equal(t, "orders process", consumer.Name)
+	assert.Equal(t, producer.SpanContext.TraceID(), consumer.SpanContext.TraceID())
+	require.Len(t, consumer.Links, 1)
+	assert.Equal(t, producer.SpanContext.SpanID(), consumer.Links[0].SpanContext.SpanID())
+	assert.Equal(t, codes.Error, consumer.Status.Code)
+}
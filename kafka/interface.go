@@ -9,7 +9,29 @@ type IPublisher interface {
 	Close()
 }
 
+// ContextPublisher is an IPublisher that can also propagate the request ID and
+// trace of a context through record headers. Publishers returned by
+// NewSyncPublisher and NewAsyncPublisher implement it.
+//
+// Example:
+//
+//	if p, ok := publisher.(kafka.ContextPublisher); ok {
+//	    err = p.WriteContext(ctx, order, key, "orders")
+//	}
+type ContextPublisher interface {
+	IPublisher
+	WriteContext(ctx context.Context, value interface{}, key, topic string) error
+}
+
 type ISubscriber interface {
 	Read(callback func(context.Context, string, []byte) error)
 	Close()
 }
+
+// ContextSubscriber is an ISubscriber that can also consume until a context is
+// cancelled instead of until a signal is received. Subscribers returned by
+// NewSubscriber implement it.
+type ContextSubscriber interface {
+	ISubscriber
+	ReadContext(ctx context.Context, callback func(context.Context, string, []byte) error)
+}
@@ -0,0 +1,16 @@
+package kafka
+
+import (
+	"github.com/anthanhphan/saturday/metrics"
+	"go.uber.org/zap"
+)
+
+// observeMessageSize records the size of a published message and warns when it
+// exceeds the warning size or the producer maximum.
+func observeMessageSize(log *zap.SugaredLogger, topic string, size int, maxMessageBytes int64) {
+	oversized := int64(size) > warningMessageSize || int64(size) >= maxMessageBytes
+	if oversized {
+		log.Warnf("topic: %v, messageLen=%v, maxMessageByte=%v", topic, size, maxMessageBytes)
+	}
+	metrics.Global().ObserveMessageSize(topic, size, oversized)
+}
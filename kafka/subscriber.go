@@ -4,9 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
-	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"time"
 
@@ -14,7 +12,7 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ ISubscriber = (*subscriber)(nil)
+var _ ContextSubscriber = (*subscriber)(nil)
 
 type subscriber struct {
 	consumer sarama.ConsumerGroup
@@ -49,7 +47,7 @@ func NewSubscriber(cfg Config) ISubscriber {
 		config.Net.SASL.Enable = true
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
 		config.Net.SASL.User = cfg.Acl.User
-		config.Net.SASL.Password = cfg.Acl.Password
+		config.Net.SASL.Password = cfg.Acl.Password.Value()
 		config.Net.SASL.Handshake = true
 		config.Net.TLS.Enable = false
 		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
@@ -91,23 +89,35 @@ func NewSubscriber(cfg Config) ISubscriber {
 	}
 }
 
+// Read consumes the configured topics until SIGINT or SIGTERM is received.
+// Applications that manage signals themselves should use ReadContext instead.
 func (sub *subscriber) Read(callback func(context.Context, string, []byte) error) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	sub.ReadContext(ctx, callback)
+}
+
+// ReadContext consumes the configured topics until ctx is cancelled or the
+// consumer group stops, then closes the subscriber. It installs no signal handler.
+func (sub *subscriber) ReadContext(ctx context.Context, callback func(context.Context, string, []byte) error) {
 	if sub.consumer == nil {
 		zap.S().Error("consumer nil -> missing consumer")
 		return
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	handler := &consumerHandler{
 		fc:    callback,
 		ready: make(chan bool),
 		group: sub.group,
 	}
+	ready := handler.ready
 
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
+	done := make(chan struct{})
 	go func() {
-		defer wg.Done()
+		defer close(done)
 		for {
 			if err := sub.consumer.Consume(ctx, sub.topics, handler); err != nil {
 				zap.S().Errorf("kafka consume topics err: %v", err)
@@ -121,22 +131,23 @@ func (sub *subscriber) Read(callback func(context.Context, string, []byte) error
 		}
 	}()
 
-	<-handler.ready
-	zap.S().Debug("kafka consumer up and running!...")
-
-	sigterm := make(chan os.Signal, 1)
-	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-ready:
+		zap.S().Debug("kafka consumer up and running!...")
+	case <-ctx.Done():
+	case <-done:
+	}
 
 	select {
 	case <-ctx.Done():
 		zap.S().Info("terminating: context cancelled - ", sub.group)
-	case <-sigterm:
-		zap.S().Info("terminating: via signal - ", sub.group)
 		sub.consumer.PauseAll()
+	case <-done:
+		zap.S().Info("terminating: consumer stopped - ", sub.group)
 	}
 
 	cancel()
-	wg.Wait()
+	<-done
 	sub.Close()
 }
 
@@ -1,14 +1,17 @@
 package kafka
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/anthanhphan/saturday/logger"
+	"github.com/anthanhphan/saturday/metrics"
 	"go.uber.org/zap"
 )
 
-var _ IPublisher = (*syncPublisher)(nil)
+var _ ContextPublisher = (*syncPublisher)(nil)
 
 type syncPublisher struct {
 	producer        sarama.SyncProducer
@@ -33,7 +36,7 @@ func NewSyncPublisher(cfg Config) IPublisher {
 		config.Net.SASL.Enable = true
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
 		config.Net.SASL.User = cfg.Acl.User
-		config.Net.SASL.Password = cfg.Acl.Password
+		config.Net.SASL.Password = cfg.Acl.Password.Value()
 		config.Net.SASL.Handshake = true
 		config.Net.TLS.Enable = false
 		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
@@ -64,10 +67,12 @@ func (p *syncPublisher) Write(v interface{}, topic string) error {
 	if err != nil {
 		return err
 	}
-	if _, _, err := p.producer.SendMessage(&sarama.ProducerMessage{
+	_, _, err = p.producer.SendMessage(&sarama.ProducerMessage{
 		Topic: topic,
 		Value: sarama.ByteEncoder(data),
-	}); err != nil {
+	})
+	p.observe(log, topic, len(data), err)
+	if err != nil {
 		log.Errorf("SendMessage fail: %v", err)
 		return err
 	}
@@ -81,11 +86,13 @@ func (p *syncPublisher) WriteWithKey(v interface{}, key, topic string) error {
 	if err != nil {
 		return err
 	}
-	if _, _, err := p.producer.SendMessage(&sarama.ProducerMessage{
+	_, _, err = p.producer.SendMessage(&sarama.ProducerMessage{
 		Key:   sarama.StringEncoder(key),
 		Topic: topic,
 		Value: sarama.ByteEncoder(data),
-	}); err != nil {
+	})
+	p.observe(log, topic, len(data), err)
+	if err != nil {
 		log.Errorf("SendMessage fail: %v", err)
 		return err
 	}
@@ -95,17 +102,60 @@ func (p *syncPublisher) WriteWithKey(v interface{}, key, topic string) error {
 func (p *syncPublisher) WriteStringWithKey(v, key, topic string) error {
 	log := zap.L().With(zap.String("prefix", "WriteStringWithKey")).Sugar()
 
-	if _, _, err := p.producer.SendMessage(&sarama.ProducerMessage{
+	_, _, err := p.producer.SendMessage(&sarama.ProducerMessage{
 		Key:   sarama.StringEncoder(key),
 		Topic: topic,
 		Value: sarama.StringEncoder(v),
-	}); err != nil {
+	})
+	p.observe(log, topic, len(v), err)
+	if err != nil {
+		log.Errorf("SendMessage fail: %v", err)
+		return err
+	}
+	return nil
+}
+
+// WriteContext publishes value as JSON in a producer span, with the request ID
+// and W3C traceparent of ctx as record headers, so consumers continue the same
+// request and trace. An empty key publishes without a key.
+//
+// Parameters:
+//   - ctx: Request context, e.g. ctx.Request.Context() in a handler
+//   - v: Value to marshal
+//   - key: Message key
+//   - topic: Destination topic
+//
+// Returns:
+//   - error: Error if the value cannot be marshalled or sent
+//
+// Example:
+//
+//	err := publisher.WriteContext(ctx, order, strconv.FormatInt(order.ID, 10), "orders")
+func (p *syncPublisher) WriteContext(ctx context.Context, v interface{}, key, topic string) error {
+	log := logger.FromContext(ctx).With(zap.String("prefix", "WriteContext")).Sugar()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := startPublishSpan(ctx, topic, len(data))
+	_, _, err = p.producer.SendMessage(newMessage(ctx, data, key, topic))
+	endSpan(span, err)
+	p.observe(log, topic, len(data), err)
+	if err != nil {
 		log.Errorf("SendMessage fail: %v", err)
 		return err
 	}
 	return nil
 }
 
+// observe records the outcome and size of a sent message.
+func (p *syncPublisher) observe(log *zap.SugaredLogger, topic string, size int, err error) {
+	metrics.Global().ObservePublish(topic, err)
+	observeMessageSize(log, topic, size, p.maxMessageBytes)
+}
+
 func (p *syncPublisher) Close() {
 	p.producer.Close()
 }
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/anthanhphan/saturday/reqctx"
+	"go.uber.org/zap"
+)
+
+// Log keys of the request correlation fields
+const (
+	LogRequestIdKey = "request_id"
+	LogTraceIdKey   = "trace_id"
+)
+
+type loggerCtxKey struct{}
+
+// WithContext returns a copy of ctx carrying log, so FromContext returns it.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - log: Logger to store, usually tagged with request-scoped fields
+//
+// Returns:
+//   - context.Context: The context carrying the logger
+//
+// Example:
+//
+//	ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(zap.Int64("order_id", id)))
+func WithContext(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, log)
+}
+
+// FromContext returns the logger stored by WithContext, or the global logger
+// tagged with the request ID and trace ID of ctx, so handler logs can be
+// correlated with the access log.
+//
+// Parameters:
+//   - ctx: Request context
+//
+// Returns:
+//   - *zap.Logger: The request-scoped logger
+//
+// Example:
+//
+//	func (h *Handler) Get(ctx context.Context, req GetReq) (*Order, error) {
+//	    logger.FromContext(ctx).Sugar().Infof("loading order %d", req.ID)
+//	    ...
+//	}
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return zap.L()
+	}
+	if log, ok := ctx.Value(loggerCtxKey{}).(*zap.Logger); ok {
+		return log
+	}
+
+	var fields []zap.Field
+	if requestId := reqctx.GetRequestId(ctx); requestId != "" {
+		fields = append(fields, zap.String(LogRequestIdKey, requestId))
+	}
+	if tp, ok := reqctx.GetTraceParent(ctx); ok {
+		fields = append(fields, zap.String(LogTraceIdKey, tp.TraceId))
+	}
+	if len(fields) == 0 {
+		return zap.L()
+	}
+	return zap.L().With(fields...)
+}
@@ -0,0 +1,307 @@
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultPath is the path of the metrics route
+const DefaultPath = "/metrics"
+
+// Status label values
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// unmatchedRoute labels requests that matched no route, so raw paths never become labels.
+const unmatchedRoute = "unmatched"
+
+var global atomic.Pointer[Metrics]
+
+type Config struct {
+	// Namespace prefixes every metric name, e.g. "orders" gives orders_http_request_duration_seconds.
+	Namespace string
+	// Buckets are the duration histogram buckets in seconds. Defaults to prometheus.DefBuckets.
+	Buckets []float64
+	// DisableRuntimeMetrics omits the Go runtime and process collectors.
+	DisableRuntimeMetrics bool
+}
+
+// Metrics holds the Prometheus collectors of the HTTP server, database and Kafka
+// instrumentation, registered in its own registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration     *prometheus.HistogramVec
+	httpInFlight     prometheus.Gauge
+	queryDuration    *prometheus.HistogramVec
+	published        *prometheus.CounterVec
+	messageSize      *prometheus.HistogramVec
+	oversized        *prometheus.CounterVec
+	processDuration  *prometheus.HistogramVec
+	processingErrors *prometheus.CounterVec
+}
+
+// New creates a Metrics with a new registry. Use it directly in tests; use
+// InitMetrics to also record database and Kafka metrics.
+//
+// Parameters:
+//   - cfg: Configuration settings for the metrics
+//
+// Returns:
+//   - *Metrics: The metrics
+//
+// Example:
+//
+//	m := metrics.New(&metrics.Config{Namespace: "orders"})
+func New(cfg *Config) *Metrics {
+	buckets := cfg.Buckets
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route template and status.",
+			Buckets:   buckets,
+		}, []string{"method", "route", "status"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: cfg.Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests being served.",
+		}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Duration of database queries by operation and status.",
+			Buckets:   buckets,
+		}, []string{"operation", "status"}),
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Name:      "kafka_published_messages_total",
+			Help:      "Number of Kafka messages published by topic and status.",
+		}, []string{"topic", "status"}),
+		messageSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace,
+			Name:      "kafka_message_size_bytes",
+			Help:      "Size of published Kafka messages by topic.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 8),
+		}, []string{"topic"}),
+		oversized: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Name:      "kafka_oversized_messages_total",
+			Help:      "Number of published Kafka messages above the warning size or the producer maximum, by topic.",
+		}, []string{"topic"}),
+		processDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.Namespace,
+			Name:      "kafka_processing_duration_seconds",
+			Help:      "Duration of Kafka message processing by topic and consumer group.",
+			Buckets:   buckets,
+		}, []string{"topic", "group"}),
+		processingErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.Namespace,
+			Name:      "kafka_processing_errors_total",
+			Help:      "Number of Kafka messages whose processing failed, by topic and consumer group.",
+		}, []string{"topic", "group"}),
+	}
+
+	m.registry.MustRegister(m.httpDuration, m.httpInFlight, m.queryDuration, m.published,
+		m.messageSize, m.oversized, m.processDuration, m.processingErrors)
+	if !cfg.DisableRuntimeMetrics {
+		m.registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+	return m
+}
+
+// InitMetrics creates a Metrics and sets it up as the global metrics recorded
+// by the gzlog query instrumentation and the Kafka publishers and subscribers.
+//
+// Parameters:
+//   - cfg: Configuration settings for the metrics
+//
+// Returns:
+//   - *Metrics: The metrics
+//   - func(): A cleanup function that restores the previous global metrics
+//
+// Example:
+//
+//	m, undo := metrics.InitMetrics(&metrics.Config{Namespace: "orders"})
+//	defer undo()
+//	server := server.NewHttpServer(server.SetMetrics(m))
+func InitMetrics(cfg *Config) (*Metrics, func()) {
+	m := New(cfg)
+	previous := global.Swap(m)
+	return m, func() {
+		global.Store(previous)
+	}
+}
+
+// Global returns the metrics set up by InitMetrics, or nil. Every method of a
+// nil *Metrics is a no-op.
+//
+// Returns:
+//   - *Metrics: The global metrics
+func Global() *Metrics {
+	return global.Load()
+}
+
+// Registry returns the registry of m, e.g. to register application collectors
+// or to gather metrics in tests.
+//
+// Returns:
+//   - *prometheus.Registry: The registry
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// RegisterDatabase registers the sql.DBStats of a connection pool, e.g. open and
+// in-use connections and wait time.
+//
+// Parameters:
+//   - name: Database name, used as the db_name label
+//   - db: The connection pool
+//
+// Returns:
+//   - error: Error if a pool with the same name is already registered
+//
+// Example:
+//
+//	sqlDB, _ := db.Executor.DB()
+//	err := m.RegisterDatabase("postgres", sqlDB)
+func (m *Metrics) RegisterDatabase(name string, db *sql.DB) error {
+	if err := m.registry.Register(collectors.NewDBStatsCollector(db, name)); err != nil {
+		return fmt.Errorf("failed to register database %s: %w", name, err)
+	}
+	return nil
+}
+
+// Middleware creates a middleware that records the duration of each request by
+// method, route template and status, and the number of requests in flight.
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function recording the request
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		m.httpInFlight.Inc()
+		defer m.httpInFlight.Dec()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.httpDuration.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler returns the HTTP handler serving the metrics of m in the Prometheus format.
+//
+// Returns:
+//   - http.Handler: The handler
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// AddMetricsRoute creates a GinOption that adds the metrics route.
+//
+// Parameters:
+//   - path: Optional route path; defaults to /metrics
+//
+// Returns:
+//   - route.GinOption: Function that adds the metrics route
+//
+// Examples:
+//
+//	engine := route.NewGinEngine(m.AddMetricsRoute())
+func (m *Metrics) AddMetricsRoute(path ...string) route.GinOption {
+	p := DefaultPath
+	if len(path) > 0 && path[0] != "" {
+		p = path[0]
+	}
+	return func(e *gin.Engine) {
+		e.GET(p, gin.WrapH(m.Handler()))
+	}
+}
+
+// ObserveQuery records the duration of a database query.
+//
+// Parameters:
+//   - operation: SQL operation, e.g. "SELECT"
+//   - duration: Query duration
+//   - err: Query error; record-not-found errors should be passed as nil
+func (m *Metrics) ObserveQuery(operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.queryDuration.WithLabelValues(operation, status(err)).Observe(duration.Seconds())
+}
+
+// ObservePublish records the outcome of publishing a Kafka message.
+//
+// Parameters:
+//   - topic: Destination topic
+//   - err: Publish error
+func (m *Metrics) ObservePublish(topic string, err error) {
+	if m == nil {
+		return
+	}
+	m.published.WithLabelValues(topic, status(err)).Inc()
+}
+
+// ObserveMessageSize records the size of a published Kafka message.
+//
+// Parameters:
+//   - topic: Destination topic
+//   - size: Message size in bytes
+//   - oversized: Whether the message exceeds the warning size or the producer maximum
+func (m *Metrics) ObserveMessageSize(topic string, size int, oversized bool) {
+	if m == nil {
+		return
+	}
+	m.messageSize.WithLabelValues(topic).Observe(float64(size))
+	if oversized {
+		m.oversized.WithLabelValues(topic).Inc()
+	}
+}
+
+// ObserveProcess records the processing of a consumed Kafka message.
+//
+// Parameters:
+//   - topic: Source topic
+//   - group: Consumer group
+//   - duration: Processing duration
+//   - err: Processing error
+func (m *Metrics) ObserveProcess(topic, group string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.processDuration.WithLabelValues(topic, group).Observe(duration.Seconds())
+	if err != nil {
+		m.processingErrors.WithLabelValues(topic, group).Inc()
+	}
+}
+
+// status returns the status label of an operation result.
+func status(err error) string {
+	if err != nil {
+		return StatusFailure
+	}
+	return StatusSuccess
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anthanhphan/saturday/http/constant/method"
+	"github.com/anthanhphan/saturday/http/route"
+	"github.com/gin-gonic/gin"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New(&Config{Namespace: "test", DisableRuntimeMetrics: true})
+
+	engine := route.NewGinEngine(
+		route.AddMiddlewares(m.Middleware()),
+		route.AddRoutes([]route.Route{{
+			Method:  method.GET,
+			Path:    "/users/:id",
+			Handler: func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) },
+		}}),
+		m.AddMetricsRoute(),
+	)
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+	assert.Zero(t, testutil.ToFloat64(m.httpInFlight))
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DefaultPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `test_http_request_duration_seconds_count{method="GET",route="/users/:id",status="204"} 2`)
+	assert.Contains(t, recorder.Body.String(), `test_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestObserve(t *testing.T) {
+	m := New(&Config{DisableRuntimeMetrics: true})
+
+	m.ObserveQuery("SELECT", 10*time.Millisecond, nil)
+	m.ObservePublish("orders", nil)
+	m.ObservePublish("orders", errors.New("broker down"))
+	m.ObserveMessageSize("orders", 2<<20, true)
+	m.ObserveProcess("orders", "billing", time.Second, errors.New("failed"))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(m.queryDuration))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.published.WithLabelValues("orders", StatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.published.WithLabelValues("orders", StatusFailure)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.oversized.WithLabelValues("orders")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.processingErrors.WithLabelValues("orders", "billing")))
+
+	// A nil Metrics, as returned by Global before InitMetrics, records nothing
+	var disabled *Metrics
+	assert.NotPanics(t, func() { disabled.ObservePublish("orders", nil) })
+}
+
+func TestInitMetrics(t *testing.T) {
+	m, undo := InitMetrics(&Config{DisableRuntimeMetrics: true})
+	assert.Same(t, m, Global())
+	undo()
+	assert.Nil(t, Global())
+}
+
+func TestRegisterDatabase(t *testing.T) {
+	m := New(&Config{DisableRuntimeMetrics: true})
+
+	db, err := sql.Open("pgx", "postgres://localhost:1/test")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, m.RegisterDatabase("main", db))
+	assert.Error(t, m.RegisterDatabase("main", db))
+
+	problems, err := testutil.GatherAndLint(m.Registry())
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	count, err := testutil.GatherAndCount(m.Registry(), "go_sql_open_connections")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Cursor points between two items of a sorted list: at the item holding
+// Values, the values of its sort fields. Sort records the sort the cursor was
+// made for, so it is not applied to a list sorted otherwise.
+type Cursor struct {
+	Values   []any  `json:"v"`           // Sort field values of the item, in sort order
+	Sort     []Sort `json:"s,omitempty"` // Resolved sort of the list, ending with its unique key
+	Backward bool   `json:"b,omitempty"` // Whether the page holds the items before the item instead of after it
+}
+
+// Encode returns the opaque representation of the cursor sent to clients.
+//
+// Returns:
+//   - string: The encoded cursor
+//   - error: Error if a value cannot be encoded
+//
+// Example:
+//
+//	next, err := pagination.Cursor{
+//	    Values: []any{order.CreatedAt, order.ID},
+//	    Sort:   []pagination.Sort{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}},
+//	}.Encode()
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor returned by Cursor.Encode. Numbers are decoded
+// as int64 when integral and float64 otherwise; times are decoded as their
+// RFC 3339 strings.
+//
+// Parameters:
+//   - value: The encoded cursor
+//
+// Returns:
+//   - Cursor: The decoded cursor
+//   - error: Error wrapping ErrInvalidParams if value is not a valid cursor
+func DecodeCursor(value string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidParams)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var cursor Cursor
+	if err := decoder.Decode(&cursor); err != nil || len(cursor.Values) == 0 {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidParams)
+	}
+
+	for i, v := range cursor.Values {
+		switch v := v.(type) {
+		case string, bool:
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				cursor.Values[i] = n
+			} else if f, err := v.Float64(); err == nil {
+				cursor.Values[i] = f
+			}
+		default:
+			// Only scalars are sort field values
+			return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidParams)
+		}
+	}
+	return cursor, nil
+}
@@ -0,0 +1,259 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default page sizes
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Filter operators
+const (
+	OpEq  = "eq"
+	OpNe  = "ne"
+	OpGt  = "gt"
+	OpGte = "gte"
+	OpLt  = "lt"
+	OpLte = "lte"
+	OpIn  = "in"
+)
+
+// ErrInvalidParams is wrapped by every error about malformed pagination parameters.
+var ErrInvalidParams = errors.New("invalid pagination parameters")
+
+var (
+	fieldPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	filterPattern = regexp.MustCompile(`^filter\[([^\]]+)\](?:\[([^\]]+)\])?$`)
+	operators     = map[string]bool{OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true, OpIn: true}
+)
+
+// Sort orders a list by a field.
+type Sort struct {
+	Field string `json:"f"`           // Field name as sent by the client
+	Desc  bool   `json:"d,omitempty"` // Whether the order is descending
+}
+
+// Filter restricts a list to items whose field matches a value.
+type Filter struct {
+	Field  string   // Field name as sent by the client
+	Op     string   // Operator, e.g. OpEq
+	Values []string // Values compared with; several only for OpIn
+}
+
+// Params are the parsed pagination query parameters. Requests with a page
+// number use offset pagination; others use cursor pagination, starting at the
+// first page when Cursor is nil.
+type Params struct {
+	Page    int     // 1-based page number of offset pagination, 0 for cursor pagination
+	Limit   int     // Maximum number of items of the page
+	Cursor  *Cursor // Decoded cursor of cursor pagination
+	Sort    []Sort
+	Filters []Filter
+}
+
+// Offset returns the number of items before the page of offset pagination.
+//
+// Returns:
+//   - int: The offset, 0 for cursor pagination
+func (p Params) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.Limit
+}
+
+// PageInfo describes a returned page.
+type PageInfo struct {
+	Limit      int    `json:"limit"`                 // Maximum number of items of the page
+	Page       int    `json:"page,omitempty"`        // Page number of offset pagination
+	Total      *int64 `json:"total,omitempty"`       // Number of matching items, when counted
+	HasNext    bool   `json:"has_next"`              // Whether a next page exists
+	HasPrev    bool   `json:"has_prev"`              // Whether a previous page exists
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor of the next page
+	PrevCursor string `json:"prev_cursor,omitempty"` // Cursor of the previous page
+}
+
+// Option is a function type that modifies the Parse configuration.
+type Option func(*options)
+
+type options struct {
+	defaultLimit int
+	maxLimit     int
+}
+
+// WithDefaultLimit returns an Option that sets the limit of requests without one.
+// Default is DefaultLimit.
+//
+// Parameters:
+//   - limit: The default limit
+//
+// Returns:
+//   - Option: Function that sets the default limit
+func WithDefaultLimit(limit int) Option {
+	return func(o *options) {
+		o.defaultLimit = limit
+	}
+}
+
+// WithMaxLimit returns an Option that caps the limit clients may request.
+// Larger limits are lowered to it. Default is MaxLimit.
+//
+// Parameters:
+//   - limit: The maximum limit
+//
+// Returns:
+//   - Option: Function that sets the maximum limit
+func WithMaxLimit(limit int) Option {
+	return func(o *options) {
+		o.maxLimit = limit
+	}
+}
+
+// Parse parses the pagination query parameters:
+//   - page: 1-based page number, selecting offset pagination
+//   - limit: Page size
+//   - cursor: Opaque cursor of a previous PageInfo, selecting cursor pagination
+//   - sort: Comma separated fields, prefixed with "-" for descending order, e.g. "-created_at,id"
+//   - filter[field] or filter[field][op]: Filter on a field, op being eq (default), ne, gt, gte, lt, lte or in
+//     with comma separated values
+//
+// Field names are only checked to be identifiers; the allowed fields are
+// decided by the code applying the parameters, e.g. postgres.PageConfig.
+//
+// Parameters:
+//   - query: The query parameters
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - Params: The parsed parameters
+//   - error: Error wrapping ErrInvalidParams if a parameter is malformed
+//
+// Example:
+//
+//	params, err := pagination.Parse(ctx.Request.URL.Query(), pagination.WithMaxLimit(50))
+//	// ?limit=10&sort=-created_at&filter[status][in]=paid,refunded
+//	// params.Limit == 10
+//	// params.Sort == []pagination.Sort{{Field: "created_at", Desc: true}}
+//	// params.Filters == []pagination.Filter{{Field: "status", Op: "in", Values: []string{"paid", "refunded"}}}
+func Parse(query url.Values, opts ...Option) (Params, error) {
+	o := &options{defaultLimit: DefaultLimit, maxLimit: MaxLimit}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	params := Params{Limit: o.defaultLimit}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return Params{}, fmt.Errorf("%w: limit must be a positive integer", ErrInvalidParams)
+		}
+		params.Limit = limit
+	}
+	if o.maxLimit > 0 && params.Limit > o.maxLimit {
+		params.Limit = o.maxLimit
+	}
+
+	if value := query.Get("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return Params{}, fmt.Errorf("%w: page must be a positive integer", ErrInvalidParams)
+		}
+		// The offset of the page must fit in an int
+		if page-1 > math.MaxInt/params.Limit {
+			return Params{}, fmt.Errorf("%w: page is too large", ErrInvalidParams)
+		}
+		params.Page = page
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if params.Page > 0 {
+			return Params{}, fmt.Errorf("%w: page and cursor cannot be combined", ErrInvalidParams)
+		}
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return Params{}, err
+		}
+		params.Cursor = &cursor
+	}
+
+	sort, err := parseSort(query.Get("sort"))
+	if err != nil {
+		return Params{}, err
+	}
+	params.Sort = sort
+
+	filters, err := parseFilters(query)
+	if err != nil {
+		return Params{}, err
+	}
+	params.Filters = filters
+
+	return params, nil
+}
+
+// parseSort parses a sort parameter such as "-created_at,id".
+func parseSort(value string) ([]Sort, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var sorts []Sort
+	for _, part := range strings.Split(value, ",") {
+		sort := Sort{Field: strings.TrimSpace(part)}
+		if strings.HasPrefix(sort.Field, "-") {
+			sort.Field, sort.Desc = sort.Field[1:], true
+		}
+		if !fieldPattern.MatchString(sort.Field) {
+			return nil, fmt.Errorf("%w: invalid sort field %q", ErrInvalidParams, sort.Field)
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+// parseFilters parses the filter[field] and filter[field][op] parameters,
+// ordered by key so the resulting query is stable.
+func parseFilters(query url.Values) ([]Filter, error) {
+	var keys []string
+	for key := range query {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var filters []Filter
+	for _, key := range keys {
+		match := filterPattern.FindStringSubmatch(key)
+		if match == nil || !fieldPattern.MatchString(match[1]) {
+			return nil, fmt.Errorf("%w: invalid filter %q", ErrInvalidParams, key)
+		}
+
+		filter := Filter{Field: match[1], Op: OpEq}
+		if match[2] != "" {
+			filter.Op = match[2]
+		}
+		if !operators[filter.Op] {
+			return nil, fmt.Errorf("%w: unknown filter operator %q", ErrInvalidParams, filter.Op)
+		}
+
+		value := query.Get(key)
+		if filter.Op == OpIn {
+			filter.Values = strings.Split(value, ",")
+		} else {
+			filter.Values = []string{value}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
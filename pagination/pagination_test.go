@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cursor, err := Cursor{Values: []any{"2024-05-01T10:00:00Z", 42}}.Encode()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   string
+		opts    []Option
+		want    Params
+		wantErr bool
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  Params{Limit: DefaultLimit},
+		},
+		{
+			name:  "offset",
+			query: "page=3&limit=10",
+			want:  Params{Page: 3, Limit: 10},
+		},
+		{
+			name:  "limit capped",
+			query: "limit=500",
+			opts:  []Option{WithMaxLimit(50)},
+			want:  Params{Limit: 50},
+		},
+		{
+			name:  "default limit",
+			query: "",
+			opts:  []Option{WithDefaultLimit(5)},
+			want:  Params{Limit: 5},
+		},
+		{
+			name:  "cursor",
+			query: "cursor=" + cursor,
+			want:  Params{Limit: DefaultLimit, Cursor: &Cursor{Values: []any{"2024-05-01T10:00:00Z", int64(42)}}},
+		},
+		{
+			name:  "sort and filters",
+			query: "sort=-created_at,id&filter[status][in]=paid,refunded&filter[amount][gte]=100&filter[currency]=VND",
+			want: Params{
+				Limit: DefaultLimit,
+				Sort:  []Sort{{Field: "created_at", Desc: true}, {Field: "id"}},
+				Filters: []Filter{
+					{Field: "amount", Op: OpGte, Values: []string{"100"}},
+					{Field: "currency", Op: OpEq, Values: []string{"VND"}},
+					{Field: "status", Op: OpIn, Values: []string{"paid", "refunded"}},
+				},
+			},
+		},
+		{name: "invalid limit", query: "limit=0", wantErr: true},
+		{name: "invalid page", query: "page=abc", wantErr: true},
+		{name: "page too large", query: "page=" + strconv.Itoa(math.MaxInt) + "&limit=10", wantErr: true},
+		{name: "page and cursor", query: "page=2&cursor=" + cursor, wantErr: true},
+		{name: "malformed cursor", query: "cursor=not-a-cursor", wantErr: true},
+		{name: "injected sort field", query: "sort=id%3Bdrop%20table%20orders", wantErr: true},
+		{name: "unknown operator", query: "filter[status][like]=p%25", wantErr: true},
+		{name: "malformed filter", query: "filter[status", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			params, err := Parse(query, tt.opts...)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidParams)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, params)
+		})
+	}
+}
+
+func TestParamsOffset(t *testing.T) {
+	assert.Equal(t, 0, Params{Limit: 10}.Offset())
+	assert.Equal(t, 0, Params{Page: 1, Limit: 10}.Offset())
+	assert.Equal(t, 20, Params{Page: 3, Limit: 10}.Offset())
+}
+
+func TestCursor(t *testing.T) {
+	encoded, err := Cursor{Values: []any{"paid", 1.5, int64(1) << 60, true}, Sort: []Sort{{Field: "status", Desc: true}}, Backward: true}.Encode()
+	require.NoError(t, err)
+
+	cursor, err := DecodeCursor(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{Values: []any{"paid", 1.5, int64(1) << 60, true}, Sort: []Sort{{Field: "status", Desc: true}}, Backward: true}, cursor)
+
+	nested, err := Cursor{Values: []any{map[string]any{"a": 1}}}.Encode()
+	require.NoError(t, err)
+	_, err = DecodeCursor(nested)
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
@@ -0,0 +1,136 @@
+// Package reqctx carries the request ID and W3C trace parent of a request in a
+// context.Context. It does not depend on any HTTP framework, so loggers and
+// clients can read them.
+package reqctx
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anthanhphan/saturday/http/constant/ctxkey"
+)
+
+// TraceParent is a parsed W3C Trace Context traceparent header,
+// e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+//
+// Fields:
+//   - TraceId: 32 lowercase hex characters identifying the trace
+//   - ParentId: 16 lowercase hex characters identifying the caller's span
+//   - Flags: Trace flags, 0x01 meaning sampled
+type TraceParent struct {
+	TraceId  string
+	ParentId string
+	Flags    byte
+}
+
+// ParseTraceParent parses a traceparent header value.
+//
+// Parameters:
+//   - value: The header value
+//
+// Returns:
+//   - TraceParent: The parsed trace parent
+//   - error: Error if the value is malformed or uses the all-zero trace or parent ID
+//
+// Example:
+//
+//	tp, err := reqctx.ParseTraceParent(ctx.GetHeader("traceparent"))
+func ParseTraceParent(value string) (TraceParent, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceParent{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+
+	version, traceId, parentId, flags := parts[0], parts[1], parts[2], parts[3]
+	// Version ff is forbidden; future versions may append fields
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceParent{}, fmt.Errorf("invalid traceparent version %q", version)
+	}
+	if !isLowerHex(traceId, 32) || traceId == strings.Repeat("0", 32) {
+		return TraceParent{}, errors.New("invalid traceparent trace id")
+	}
+	if !isLowerHex(parentId, 16) || parentId == strings.Repeat("0", 16) {
+		return TraceParent{}, errors.New("invalid traceparent parent id")
+	}
+	if !isLowerHex(flags, 2) {
+		return TraceParent{}, errors.New("invalid traceparent flags")
+	}
+
+	flag, _ := hex.DecodeString(flags)
+	return TraceParent{TraceId: traceId, ParentId: parentId, Flags: flag[0]}, nil
+}
+
+// String formats the trace parent as a version 00 traceparent header value.
+func (tp TraceParent) String() string {
+	return fmt.Sprintf("00-%s-%s-%02x", tp.TraceId, tp.ParentId, tp.Flags)
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tp TraceParent) Sampled() bool {
+	return tp.Flags&0x01 == 0x01
+}
+
+// isLowerHex reports whether s is n lowercase hex characters.
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// SetRequestId returns a copy of ctx carrying the request ID under ctxkey.CtxRequestIdKey.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - requestId: The request ID
+//
+// Returns:
+//   - context.Context: The context carrying the request ID
+func SetRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, ctxkey.CtxRequestIdKey, requestId)
+}
+
+// GetRequestId returns the request ID of ctx, or "" if there is none.
+//
+// Parameters:
+//   - ctx: The context
+//
+// Returns:
+//   - string: The request ID
+func GetRequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(ctxkey.CtxRequestIdKey).(string)
+	return requestId
+}
+
+// SetTraceParent returns a copy of ctx carrying the trace parent under ctxkey.CtxTraceParentKey.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - tp: The trace parent
+//
+// Returns:
+//   - context.Context: The context carrying the trace parent
+func SetTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, ctxkey.CtxTraceParentKey, tp)
+}
+
+// GetTraceParent returns the trace parent of ctx.
+//
+// Parameters:
+//   - ctx: The context
+//
+// Returns:
+//   - TraceParent: The trace parent
+//   - bool: false if ctx carries no trace parent
+func GetTraceParent(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(ctxkey.CtxTraceParentKey).(TraceParent)
+	return tp, ok
+}
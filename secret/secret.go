@@ -0,0 +1,38 @@
+package secret
+
+import "fmt"
+
+const redacted = "******"
+
+// Secret is a string that hides its value when printed or marshalled, so
+// configuration structs holding passwords can be logged safely. Use string(s)
+// or Value to read the actual value.
+type Secret string
+
+// Value returns the actual secret value.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String implements fmt.Stringer and masks non-empty values.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString implements fmt.GoStringer so %#v masks the value as well.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// MarshalJSON implements json.Marshaler and masks the value, which also covers zap.Any.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", s.String())), nil
+}
+
+// MarshalYAML implements yaml.Marshaler and masks the value.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
@@ -0,0 +1,31 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	cfg := struct {
+		Host     string `json:"host"`
+		Password Secret `json:"password"`
+	}{Host: "localhost", Password: "p@ssw0rd"}
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+
+	for _, out := range []string{
+		fmt.Sprintf("%v", cfg),
+		fmt.Sprintf("%+v", cfg),
+		fmt.Sprintf("%#v", cfg),
+		string(data),
+	} {
+		assert.NotContains(t, out, "p@ssw0rd")
+		assert.Contains(t, out, redacted)
+	}
+	assert.Equal(t, "", Secret("").String())
+}
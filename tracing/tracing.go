@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/anthanhphan/saturday/reqctx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer used by saturday's instrumentation.
+const InstrumentationName = "github.com/anthanhphan/saturday"
+
+const traceParentHeader = "traceparent"
+
+type Config struct {
+	// ServiceName is recorded as the service.name resource attribute of every span.
+	ServiceName string
+	// SampleRatio is the fraction of new traces that are recorded. Values <= 0 or >= 1
+	// record every trace. Traces continued from a caller follow the caller's decision.
+	SampleRatio float64
+}
+
+// InitTracer creates an OpenTelemetry tracer provider exporting spans to the given
+// exporters, and sets it up with the W3C Trace Context propagator as the global
+// provider used by the HTTP, GORM and Kafka instrumentation.
+//
+// Parameters:
+//   - cfg: Configuration settings for the tracer
+//   - exporters: Span exporters, e.g. an OTLP exporter or tracetest.NewInMemoryExporter() in tests
+//
+// Returns:
+//   - *sdktrace.TracerProvider: The provider; call Shutdown to flush pending spans
+//   - func(): A cleanup function that restores the previous global provider and propagator
+//
+// Example:
+//
+//	exporter, _ := otlptracegrpc.New(ctx)
+//	provider, undo := tracing.InitTracer(&tracing.Config{ServiceName: "orders"}, exporter)
+//	defer undo()
+//	defer provider.Shutdown(context.Background())
+func InitTracer(cfg *Config, exporters ...sdktrace.SpanExporter) (*sdktrace.TracerProvider, func()) {
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+	}
+	for _, exporter := range exporters {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(options...)
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider, func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}
+}
+
+// Start starts a span with the global tracer. The span also becomes the trace
+// parent of the returned context, so logger.FromContext tags log lines with its
+// trace ID and outgoing Kafka messages continue the trace.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - name: Span name
+//   - opts: Span options, e.g. trace.WithSpanKind or trace.WithAttributes
+//
+// Returns:
+//   - context.Context: The context carrying the span
+//   - trace.Span: The span; call End when the operation finishes
+//
+// Example:
+//
+//	ctx, span := tracing.Start(ctx, "charge card")
+//	defer span.End()
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(InstrumentationName).Start(ctx, name, opts...)
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = reqctx.SetTraceParent(ctx, reqctx.TraceParent{
+			TraceId:  sc.TraceID().String(),
+			ParentId: sc.SpanID().String(),
+			Flags:    byte(sc.TraceFlags()),
+		})
+	}
+	return ctx, span
+}
+
+// Extract returns a copy of ctx carrying the remote span context read from
+// carrier by the global propagator. When the carrier holds no trace context, a
+// trace parent already stored by middlewares.RequestId is used instead.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - carrier: Carrier of the inbound headers, e.g. propagation.HeaderCarrier
+//
+// Returns:
+//   - context.Context: The context carrying the remote span context
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	if tp, ok := reqctx.GetTraceParent(ctx); ok {
+		return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentHeader: tp.String()})
+	}
+	return ctx
+}
+
+// Inject writes the span context of ctx into carrier with the global propagator.
+// When the propagator writes no traceparent, e.g. before InitTracer is called,
+// the trace parent stored in ctx is written instead.
+//
+// Parameters:
+//   - ctx: Context carrying the span
+//   - carrier: Carrier of the outbound headers
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if carrier.Get(traceParentHeader) != "" {
+		return
+	}
+	if tp, ok := reqctx.GetTraceParent(ctx); ok {
+		carrier.Set(traceParentHeader, tp.String())
+	}
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anthanhphan/saturday/reqctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestStart(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, undo := InitTracer(&Config{ServiceName: "test"}, exporter)
+	defer undo()
+
+	ctx := Extract(context.Background(), propagation.MapCarrier{"traceparent": testTraceParent})
+	ctx, span := Start(ctx, "work")
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "work", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+
+	// The span becomes the trace parent used by logs and outgoing messages
+	tp, ok := reqctx.GetTraceParent(ctx)
+	require.True(t, ok)
+	assert.Equal(t, spans[0].SpanContext.SpanID().String(), tp.ParentId)
+
+	carrier := propagation.MapCarrier{}
+	Inject(ctx, carrier)
+	assert.Equal(t, tp.String(), carrier["traceparent"])
+}
+
+func TestPropagationWithoutTracer(t *testing.T) {
+	tp, err := reqctx.ParseTraceParent(testTraceParent)
+	require.NoError(t, err)
+	ctx := reqctx.SetTraceParent(context.Background(), tp)
+
+	// Without a provider the stored trace parent is still propagated
+	carrier := propagation.MapCarrier{}
+	Inject(ctx, carrier)
+	assert.Equal(t, testTraceParent, carrier["traceparent"])
+
+	extracted := Extract(ctx, propagation.MapCarrier{})
+	assert.Equal(t, tp.TraceId, trace.SpanContextFromContext(extracted).TraceID().String())
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+// AesGcmEncrypt encrypts plaintext with AES-GCM. A random nonce is generated
+// and prepended to the returned ciphertext.
+//
+// Parameters:
+//   - key: The AES key; must be 16, 24 or 32 bytes long
+//   - plaintext: The data to encrypt
+//
+// Returns:
+//   - []byte: The nonce followed by the encrypted data
+//   - error: Any error that occurred during encryption
+//
+// Examples:
+//
+//	key := make([]byte, 32)
+//	ciphertext, err := AesGcmEncrypt(key, []byte("secret"))
+func AesGcmEncrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGcm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// AesGcmDecrypt decrypts ciphertext produced by AesGcmEncrypt.
+//
+// Parameters:
+//   - key: The AES key used for encryption
+//   - ciphertext: The nonce followed by the encrypted data
+//
+// Returns:
+//   - []byte: The decrypted data
+//   - error: Any error that occurred during decryption, including authentication failures
+//
+// Examples:
+//
+//	plaintext, err := AesGcmDecrypt(key, ciphertext)
+//	fmt.Println(string(plaintext)) // prints "secret"
+func AesGcmDecrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGcm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, data := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, data, nil)
+}
+
+func newGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAesGcmEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	ciphertext, err := AesGcmEncrypt(key, []byte("secret"))
+	if err != nil {
+		t.Fatalf("AesGcmEncrypt returned an error: %v", err)
+	}
+
+	plaintext, err := AesGcmDecrypt(key, ciphertext)
+	if err != nil {
+		t.Fatalf("AesGcmDecrypt returned an error: %v", err)
+	}
+	if string(plaintext) != "secret" {
+		t.Errorf("AesGcmDecrypt = %q, want %q", plaintext, "secret")
+	}
+}
+
+func TestAesGcmDecryptErrors(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	ciphertext, err := AesGcmEncrypt(key, []byte("secret"))
+	if err != nil {
+		t.Fatalf("AesGcmEncrypt returned an error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		key        []byte
+		ciphertext []byte
+	}{
+		{"wrong key", bytes.Repeat([]byte{2}, 32), ciphertext},
+		{"invalid key size", []byte("short"), ciphertext},
+		{"too short", key, []byte{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AesGcmDecrypt(tt.key, tt.ciphertext); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
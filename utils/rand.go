@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
+	"time"
 )
 
 // RandString generates a random hex string of the specified length.
@@ -52,3 +53,40 @@ func RandInt64() (int64, error) {
 	// Convert bytes to int64 using binary.BigEndian
 	return int64(binary.BigEndian.Uint64(buf[:])), nil
 }
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID generates a ULID: a 26 character, lexicographically sortable
+// identifier made of a 48-bit millisecond timestamp and 80 random bits.
+//
+// Returns:
+//   - string: The generated ULID
+//   - error: Any error that occurred during random number generation
+//
+// Examples:
+//
+//	NewULID()    // returns "01ARZ3NDEKTSV4RRFFQ69G5FAV", nil
+func NewULID() (string, error) {
+	var data [16]byte
+	if _, err := rand.Read(data[6:]); err != nil {
+		return "", err
+	}
+
+	ms := uint64(time.Now().UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+
+	// Encode the 128 bits as 26 base32 characters, most significant first
+	hi := binary.BigEndian.Uint64(data[:8])
+	lo := binary.BigEndian.Uint64(data[8:])
+	id := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		id[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(id), nil
+}
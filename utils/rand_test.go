@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRandString(t *testing.T) {
@@ -57,3 +59,29 @@ func TestRandInt64(t *testing.T) {
 		seen[got] = true
 	}
 }
+
+func TestNewULID(t *testing.T) {
+	first, err := NewULID()
+	if err != nil {
+		t.Fatalf("NewULID() error = %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	second, err := NewULID()
+	if err != nil {
+		t.Fatalf("NewULID() error = %v", err)
+	}
+
+	for _, id := range []string{first, second} {
+		if len(id) != 26 {
+			t.Errorf("NewULID() length = %d, want 26", len(id))
+		}
+		if strings.Trim(id, crockfordAlphabet) != "" {
+			t.Errorf("NewULID() = %s contains characters outside the Crockford alphabet", id)
+		}
+	}
+
+	// ULIDs created in later milliseconds sort after earlier ones
+	if first >= second {
+		t.Errorf("NewULID() = %s not sorted after %s", second, first)
+	}
+}
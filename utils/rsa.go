@@ -3,8 +3,11 @@ package utils
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
+	"fmt"
 )
 
 type RsaKey struct {
@@ -61,3 +64,64 @@ func GenerateRsaKeyPair() (RsaKey, error) {
 
 	return rsaKey, nil
 }
+
+// RsaEncrypt encrypts plaintext with a PEM-encoded public key using RSA-OAEP with SHA-256.
+// It accepts public keys in the format produced by GenerateRsaKeyPair.
+//
+// Parameters:
+//   - publicKey: PEM-encoded RSA public key
+//   - plaintext: The data to encrypt
+//
+// Returns:
+//   - []byte: The encrypted data
+//   - error: Any error that occurred during key parsing or encryption
+//
+// Examples:
+//
+//	ciphertext, err := RsaEncrypt(keys.PublicKey, []byte("secret"))
+func RsaEncrypt(publicKey string, plaintext []byte) ([]byte, error) {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return nil, errors.New("failed to decode public key PEM")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaKey, plaintext, nil)
+}
+
+// RsaDecrypt decrypts ciphertext produced by RsaEncrypt with a PEM-encoded private key.
+//
+// Parameters:
+//   - privateKey: PEM-encoded PKCS#1 RSA private key
+//   - ciphertext: The data to decrypt
+//
+// Returns:
+//   - []byte: The decrypted data
+//   - error: Any error that occurred during key parsing or decryption
+//
+// Examples:
+//
+//	plaintext, err := RsaDecrypt(keys.PrivateKey, ciphertext)
+//	fmt.Println(string(plaintext)) // prints "secret"
+func RsaDecrypt(privateKey string, ciphertext []byte) ([]byte, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext, nil)
+}
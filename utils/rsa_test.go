@@ -43,3 +43,30 @@ func TestGenerateRSAKeys(t *testing.T) {
 		t.Error("Public key does not correspond to the private key")
 	}
 }
+
+func TestRsaEncryptDecrypt(t *testing.T) {
+	rsaKey, err := GenerateRsaKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateRsaKeyPair returned an error: %v", err)
+	}
+
+	ciphertext, err := RsaEncrypt(rsaKey.PublicKey, []byte("secret"))
+	if err != nil {
+		t.Fatalf("RsaEncrypt returned an error: %v", err)
+	}
+
+	plaintext, err := RsaDecrypt(rsaKey.PrivateKey, ciphertext)
+	if err != nil {
+		t.Fatalf("RsaDecrypt returned an error: %v", err)
+	}
+	if string(plaintext) != "secret" {
+		t.Errorf("RsaDecrypt = %q, want %q", plaintext, "secret")
+	}
+
+	if _, err := RsaEncrypt("invalid-public-key", []byte("secret")); err == nil {
+		t.Error("Expected an error for an invalid public key")
+	}
+	if _, err := RsaDecrypt("invalid-private-key", ciphertext); err == nil {
+		t.Error("Expected an error for an invalid private key")
+	}
+}